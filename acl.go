@@ -2,6 +2,7 @@ package graphdb
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 )
@@ -12,6 +13,13 @@ const (
 	PathAccessControlLists = "/rest/repositories/%s/acl"
 )
 
+const (
+	ScopeStatement  = "statement"
+	ScopeClearGraph = "clear_graph"
+	ScopePlugin     = "plugin"
+	ScopeSystem     = "system"
+)
+
 type Policy interface {
 	PolicyType() string
 }
@@ -57,13 +65,74 @@ func (s ClearGraphEntry) PolicyType() string {
 	return s.PolicyName
 }
 
+// Policies is a list of ACL policies, that unmarshals each entry into the concrete type matching its scope.
+type Policies []Policy
+
+func (p *Policies) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	policies := make(Policies, 0, len(raw))
+	for _, r := range raw {
+		var scope struct {
+			Scope string `json:"scope"`
+		}
+		if err := json.Unmarshal(r, &scope); err != nil {
+			return err
+		}
+
+		policy, err := unmarshalPolicy(scope.Scope, r)
+		if err != nil {
+			return err
+		}
+		policies = append(policies, policy)
+	}
+
+	*p = policies
+	return nil
+}
+
+func unmarshalPolicy(scope string, data []byte) (Policy, error) {
+	var policy Policy
+	var err error
+	switch scope {
+	case ScopeStatement:
+		var v StatementStatement
+		err = json.Unmarshal(data, &v)
+		policy = v
+	case ScopeClearGraph:
+		var v ClearGraphEntry
+		err = json.Unmarshal(data, &v)
+		policy = v
+	case ScopePlugin:
+		var v PluginPolicy
+		err = json.Unmarshal(data, &v)
+		policy = v
+	default:
+		var v SystemPolicy
+		err = json.Unmarshal(data, &v)
+		policy = v
+	}
+	return policy, err
+}
+
 type AclClient struct {
 	client *Client
 }
 
-func (a *AclClient) List(ctx context.Context, id string, conf ...RequestConfig) error {
-	return a.client.get(ctx, fmt.Sprintf(PathAccessControlLists, id), func(resp *http.Response) error {
-		return ErrNotStatus(http.StatusCreated, "acl", resp)
+func (a *AclClient) List(ctx context.Context, id string, conf ...RequestConfig) (Policies, error) {
+	var policies Policies
+	return policies, a.client.get(ctx, fmt.Sprintf(PathAccessControlLists, id), func(resp *http.Response) error {
+		if err := ErrNotStatus(http.StatusOK, "acl", resp); err != nil {
+			return err
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(&policies); err != nil {
+			return fmt.Errorf("acl: %w", err)
+		}
+		return nil
 	}, conf...)
 }
 
@@ -43,7 +43,7 @@ func (b *BackupClient) Create(ctx context.Context, opts BackupOptions, consumer
 		return err
 	}
 
-	rh := CombinedResponseHandler(ExpectStatusCode(http.StatusOK), func(resp *http.Response) error {
+	rh := CombinedResponseHandler(ExpectStatusCodeFor("backup", http.StatusOK), func(resp *http.Response) error {
 		if opts.BucketUri != "" {
 			return nil
 		}
@@ -83,9 +83,9 @@ func (b *BackupClient) Restore(ctx context.Context, opts RestoreOptions, r io.Re
 	parts := []Part{{Key: "params", Type: "application/json", Value: bytes.NewBuffer(data)}}
 	if opts.BucketUri == "" {
 		parts = append(parts, Part{Key: "file", Value: r})
-		return b.client.post(ctx, PathBackupRestore, nil, ExpectStatusCode(http.StatusOK), MultipartFormData(parts...))
+		return b.client.post(ctx, PathBackupRestore, nil, ExpectStatusCodeFor("restore", http.StatusOK), MultipartFormData(parts...))
 	}
-	return b.client.post(ctx, PathBackupRestoreCloud, nil, ExpectStatusCode(http.StatusOK), MultipartFormData(parts...))
+	return b.client.post(ctx, PathBackupRestoreCloud, nil, ExpectStatusCodeFor("restore", http.StatusOK), MultipartFormData(parts...))
 }
 
 // extractFilename parses a Content-Disposition header and returns the filename.
@@ -3,7 +3,7 @@ package graphdb
 import (
 	"bytes"
 	"context"
-	"go-graphdb/testenv"
+	"github.com/yaskoo/go-graphdb/testenv"
 	"io"
 	"testing"
 )
@@ -0,0 +1,276 @@
+package graphdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ErrCircuitOpen is returned for requests to a node whose circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of the circuit breaker of a node.
+type CircuitState int
+
+const (
+	// CircuitClosed lets all requests through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails all requests with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe requests through, which close the circuit when they succeed and
+	// open it again when they fail.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitBreakerPolicy configures the circuit breakers of the nodes. A request fails when it gets a transport error
+// or a 5xx status, or takes longer than SlowThreshold when it is set. The circuit of a node opens after
+// FailureThreshold consecutive failures, and goes half-open after OpenTimeout, letting through up to HalfOpenProbes
+// requests at a time.
+type CircuitBreakerPolicy struct {
+	FailureThreshold int
+	SlowThreshold    time.Duration
+	OpenTimeout      time.Duration
+	HalfOpenProbes   int
+	// OnStateChange is called when the circuit of a node changes state.
+	OnStateChange func(node string, from, to CircuitState)
+}
+
+// WithCircuitBreaker guards every node, the base url of the client or each node of a cluster client, with a circuit
+// breaker. Unset fields get defaults: 5 failures, no slow threshold, 30s open timeout and 1 probe. Cluster clients
+// route reads away from nodes with open circuits.
+func WithCircuitBreaker(policy CircuitBreakerPolicy) Option {
+	return func(client *Client) {
+		client.breakers = &breakers{policy: policy.withDefaults(), nodes: map[string]*breaker{}}
+	}
+}
+
+func (p CircuitBreakerPolicy) withDefaults() CircuitBreakerPolicy {
+	if p.FailureThreshold <= 0 {
+		p.FailureThreshold = 5
+	}
+	if p.OpenTimeout <= 0 {
+		p.OpenTimeout = 30 * time.Second
+	}
+	if p.HalfOpenProbes <= 0 {
+		p.HalfOpenProbes = 1
+	}
+	return p
+}
+
+// CircuitStates returns the state of the circuit breaker of every node the client has sent requests to, keyed by
+// the scheme and host of the node. It is nil without WithCircuitBreaker.
+func (c *Client) CircuitStates() map[string]CircuitState {
+	return c.breakers.states()
+}
+
+// breakers are the circuit breakers of the nodes. All methods are no-ops on nil breakers.
+type breakers struct {
+	policy CircuitBreakerPolicy
+
+	mu    sync.Mutex
+	nodes map[string]*breaker
+}
+
+type breaker struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probes   int
+}
+
+// guardedSend sends the request unless the circuit of its node is open, and records the outcome.
+func (c *Client) guardedSend(req *http.Request) (*http.Response, error) {
+	node := nodeOf(req)
+	probe, err := c.breakers.acquire(node)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := c.authenticatedSend(req)
+	c.breakers.release(req.Context(), node, probe, resp, err, time.Since(start))
+	return resp, err
+}
+
+func nodeOf(req *http.Request) string {
+	return req.URL.Scheme + "://" + req.URL.Host
+}
+
+// endpointNode returns the scheme and host of a node endpoint, as used to key the circuit breakers.
+func endpointNode(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// acquire fails when the circuit of the node is open, or half-open with all probes in flight. It reports whether the
+// request is a probe of a half-open circuit.
+func (b *breakers) acquire(node string) (bool, error) {
+	if b == nil {
+		return false, nil
+	}
+
+	b.mu.Lock()
+	n := b.node(node)
+	from := b.advance(n)
+	allowed := n.state == CircuitClosed || n.state == CircuitHalfOpen && n.probes < b.policy.HalfOpenProbes
+	probe := allowed && n.state == CircuitHalfOpen
+	if probe {
+		n.probes++
+	}
+	to := n.state
+	b.mu.Unlock()
+
+	b.changed(node, from, to)
+	if !allowed {
+		return false, fmt.Errorf("%s: %w", node, ErrCircuitOpen)
+	}
+	return probe, nil
+}
+
+// release records the outcome of a request acquired for the node. Requests canceled by the caller are not counted, and
+// neither are requests acquired while the circuit was closed that finish after it opened.
+func (b *breakers) release(ctx context.Context, node string, probe bool, resp *http.Response, err error, latency time.Duration) {
+	if b == nil {
+		return
+	}
+
+	canceled := err != nil && ctx.Err() != nil
+	failed := err != nil || resp.StatusCode >= http.StatusInternalServerError ||
+		b.policy.SlowThreshold > 0 && latency > b.policy.SlowThreshold
+
+	b.mu.Lock()
+	n := b.node(node)
+	from := n.state
+	halfOpen := probe && from == CircuitHalfOpen
+	if halfOpen {
+		n.probes = max(n.probes-1, 0)
+	}
+
+	switch {
+	case canceled:
+	case halfOpen && !failed:
+		n.state, n.failures = CircuitClosed, 0
+	case halfOpen:
+		n.state, n.openedAt = CircuitOpen, time.Now()
+	case from != CircuitClosed:
+	case !failed:
+		n.failures = 0
+	default:
+		n.failures++
+		if n.failures >= b.policy.FailureThreshold {
+			n.state, n.openedAt = CircuitOpen, time.Now()
+		}
+	}
+	to := n.state
+	b.mu.Unlock()
+
+	b.changed(node, from, to)
+}
+
+// available reports whether a request to the node would be let through, without acquiring it.
+func (b *breakers) available(node string) bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	n := b.node(node)
+	from := b.advance(n)
+	to := n.state
+	available := n.state == CircuitClosed || n.state == CircuitHalfOpen && n.probes < b.policy.HalfOpenProbes
+	b.mu.Unlock()
+
+	b.changed(node, from, to)
+	return available
+}
+
+func (b *breakers) states() map[string]CircuitState {
+	if b == nil {
+		return nil
+	}
+
+	type change struct {
+		node     string
+		from, to CircuitState
+	}
+
+	b.mu.Lock()
+	var changes []change
+	states := make(map[string]CircuitState, len(b.nodes))
+	for node, n := range b.nodes {
+		from := b.advance(n)
+		changes = append(changes, change{node, from, n.state})
+		states[node] = n.state
+	}
+	b.mu.Unlock()
+
+	for _, c := range changes {
+		b.changed(c.node, c.from, c.to)
+	}
+	return states
+}
+
+// node returns the breaker of the node, creating it when needed. It must be called with mu held.
+func (b *breakers) node(node string) *breaker {
+	n, ok := b.nodes[node]
+	if !ok {
+		n = &breaker{}
+		b.nodes[node] = n
+	}
+	return n
+}
+
+// advance moves an open circuit to half-open once the open timeout has passed, and returns the previous state. It must
+// be called with mu held.
+func (b *breakers) advance(n *breaker) CircuitState {
+	from := n.state
+	if n.state == CircuitOpen && time.Since(n.openedAt) >= b.policy.OpenTimeout {
+		n.state, n.probes = CircuitHalfOpen, 0
+	}
+	return from
+}
+
+func (b *breakers) changed(node string, from, to CircuitState) {
+	if from != to && b.policy.OnStateChange != nil {
+		b.policy.OnStateChange(node, from, to)
+	}
+}
+
+// observeCircuits reports the state of the circuit breakers as a gauge.
+func (t *telemetry) observeCircuits(b *breakers) {
+	if t == nil || b == nil {
+		return
+	}
+
+	// registration only fails for invalid names, in which case the state is not reported
+	_, _ = t.meter.Int64ObservableGauge("graphdb.client.circuit.state",
+		metric.WithDescription("State of the circuit breaker of a node: 0 closed, 1 open, 2 half-open."),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			for node, state := range b.states() {
+				o.Observe(int64(state), metric.WithAttributes(AttributeServer.String(node)))
+			}
+			return nil
+		}))
+}
@@ -0,0 +1,214 @@
+package graphdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	var down atomic.Bool
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if down.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var transitions []string
+	client := New(server.URL, WithCircuitBreaker(CircuitBreakerPolicy{
+		FailureThreshold: 2,
+		OpenTimeout:      50 * time.Millisecond,
+		OnStateChange: func(node string, from, to CircuitState) {
+			mu.Lock()
+			defer mu.Unlock()
+			transitions = append(transitions, from.String()+">"+to.String())
+		},
+	}))
+
+	ctx := context.Background()
+	down.Store(true)
+	for range 2 {
+		if _, err := client.Repositories().Infos(ctx); err == nil {
+			t.Fatal("expected request to fail")
+		}
+	}
+
+	if states := client.CircuitStates(); states[server.URL] != CircuitOpen {
+		t.Errorf("expected open circuit, got %v", states)
+	}
+	if _, err := client.Repositories().Infos(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+	if hits.Load() != 2 {
+		t.Errorf("expected the open circuit to skip the server, got %d requests", hits.Load())
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if states := client.CircuitStates(); states[server.URL] != CircuitHalfOpen {
+		t.Errorf("expected half-open circuit, got %v", states)
+	}
+
+	down.Store(false)
+	if _, err := client.Repositories().Infos(ctx); err != nil {
+		t.Fatalf("expected the probe to succeed: %v", err)
+	}
+	if states := client.CircuitStates(); states[server.URL] != CircuitClosed {
+		t.Errorf("expected closed circuit, got %v", states)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	expected := []string{"closed>open", "open>half-open", "half-open>closed"}
+	if len(transitions) != len(expected) {
+		t.Fatalf("expected transitions %v, got %v", expected, transitions)
+	}
+	for i := range expected {
+		if transitions[i] != expected[i] {
+			t.Errorf("expected transitions %v, got %v", expected, transitions)
+		}
+	}
+}
+
+func TestClient_CircuitBreakerSlow(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, WithCircuitBreaker(CircuitBreakerPolicy{FailureThreshold: 1, SlowThreshold: 5 * time.Millisecond}))
+	if _, err := client.Repositories().Infos(context.Background()); err != nil {
+		t.Fatalf("expected slow request to succeed: %v", err)
+	}
+	if states := client.CircuitStates(); states[server.URL] != CircuitOpen {
+		t.Errorf("expected slow request to open the circuit, got %v", states)
+	}
+}
+
+func TestNewClusterClient_CircuitBreaker(t *testing.T) {
+	cluster := newFakeCluster(t, 3)
+	cluster.nodes[1].Close()
+
+	client := NewClusterClient([]string{cluster.nodes[0].URL}, WithCircuitBreaker(CircuitBreakerPolicy{FailureThreshold: 1}))
+	ctx := context.Background()
+	for range 4 {
+		if _, err := client.Repositories().Infos(ctx); err != nil {
+			t.Fatalf("failed to list repositories: %v", err)
+		}
+	}
+
+	if states := client.CircuitStates(); states[cluster.nodes[1].URL] != CircuitOpen {
+		t.Errorf("expected open circuit for the dead follower, got %v", states)
+	}
+	for _, node := range cluster.hits[PathRepositories] {
+		if node != 2 {
+			t.Errorf("expected reads on the live follower, got nodes %v", cluster.hits[PathRepositories])
+			break
+		}
+	}
+}
+
+func TestNewClusterClient_CircuitBreakerLeader(t *testing.T) {
+	cluster := newFakeCluster(t, 2)
+	cluster.failWrites = true
+
+	client := NewClusterClient([]string{cluster.nodes[0].URL}, WithCircuitBreaker(CircuitBreakerPolicy{FailureThreshold: 1}))
+	ctx := context.Background()
+	if err := client.Repositories().Create(ctx, JsonBody(RepositoryConfig{Id: "test"})); err == nil {
+		t.Fatal("expected create to fail")
+	}
+	discoveries := len(cluster.hits[PathClusterGroupStatus])
+
+	start := time.Now()
+	err := client.Repositories().Create(ctx, JsonBody(RepositoryConfig{Id: "test"}))
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("expected the write to fail fast, took %v", elapsed)
+	}
+	if n := len(cluster.hits[PathClusterGroupStatus]); n != discoveries {
+		t.Errorf("expected no discoveries for the open circuit, got %d", n-discoveries)
+	}
+	if topology := client.Topology(); topology.Leader != cluster.nodes[0].URL {
+		t.Errorf("expected the leader to be kept, got %+v", topology)
+	}
+}
+
+func TestBreakers_ReleaseNonProbe(t *testing.T) {
+	b := &breakers{policy: CircuitBreakerPolicy{FailureThreshold: 1, OpenTimeout: time.Millisecond}.withDefaults(), nodes: map[string]*breaker{}}
+	ctx := context.Background()
+	ok := &http.Response{StatusCode: http.StatusOK}
+
+	// a slow request acquired while closed, the circuit opens and goes half-open before it finishes
+	slow, _ := b.acquire("node")
+	failing, _ := b.acquire("node")
+	b.release(ctx, "node", failing, nil, errors.New("refused"), 0)
+	time.Sleep(2 * time.Millisecond)
+
+	probe, err := b.acquire("node")
+	if err != nil || !probe || slow {
+		t.Fatalf("expected only the request of the half-open circuit to probe, got %t and %t: %v", slow, probe, err)
+	}
+
+	b.release(ctx, "node", slow, ok, nil, 0)
+	if state := b.states()["node"]; state != CircuitHalfOpen {
+		t.Errorf("expected the slow request to leave the circuit half-open, got %v", state)
+	}
+	if _, err = b.acquire("node"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected the probe to still be in flight, got %v", err)
+	}
+
+	b.release(ctx, "node", probe, ok, nil, 0)
+	if state := b.states()["node"]; state != CircuitClosed {
+		t.Errorf("expected the probe to close the circuit, got %v", state)
+	}
+}
+
+func TestNewClusterClient_CircuitBreakerCallback(t *testing.T) {
+	cluster := newFakeCluster(t, 3)
+	cluster.nodes[1].Close()
+
+	// the callback runs while requests are routed, it must be able to use the client
+	var client *Client
+	client = NewClusterClient([]string{cluster.nodes[0].URL}, WithCircuitBreaker(CircuitBreakerPolicy{
+		FailureThreshold: 1,
+		OpenTimeout:      5 * time.Millisecond,
+		OnStateChange: func(node string, from, to CircuitState) {
+			client.Topology()
+		},
+	}))
+
+	done := make(chan error)
+	go func() {
+		ctx := context.Background()
+		for range 4 {
+			if _, err := client.Repositories().Infos(ctx); err != nil {
+				done <- err
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		done <- nil
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("failed to list repositories: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the state change callback not to deadlock the client")
+	}
+}
@@ -2,8 +2,11 @@ package graphdb
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
 )
 
 type Option func(client *Client)
@@ -14,21 +17,22 @@ func WithTransport(transport http.RoundTripper) Option {
 	}
 }
 
-func WithBasicAuth(username, password string) Option {
-	return func(client *Client) {
-		client.auth = func(req *http.Request) {
-			req.SetBasicAuth(username, password)
-		}
-	}
-}
-
 type Client struct {
 	url string
 	hc  *http.Client
 
-	auth RequestConfig
+	credentials CredentialsProvider
+	polling     Polling
+	retry       RetryPolicy
+	middleware  []Middleware
+	doer        Doer
+	telemetry   *telemetry
+	logging     *logging
+	router      *router
+	breakers    *breakers
 
 	repository   *RepositoryClient
+	acl          *AclClient
 	backup       *BackupClient
 	cluster      *ClusterClient
 	security     *SecurityClient
@@ -60,7 +64,7 @@ func (c *Client) delete(ctx context.Context, path string, body io.Reader, rh Res
 	return c.do(ctx, http.MethodDelete, path, body, rh, conf...)
 }
 
-func (c *Client) do(ctx context.Context, m string, p string, b io.Reader, rh ResponseHandler, conf ...RequestConfig) error {
+func (c *Client) do(ctx context.Context, m string, p string, b io.Reader, rh ResponseHandler, conf ...RequestConfig) (err error) {
 	req, err := http.NewRequestWithContext(ctx, m, c.url+p, b)
 	if err != nil {
 		return err
@@ -70,34 +74,79 @@ func (c *Client) do(ctx context.Context, m string, p string, b io.Reader, rh Res
 		cf(req)
 	}
 
-	if c.auth != nil {
-		c.auth(req)
-	}
+	req, span := c.telemetry.startSpan(req, p)
+	req, log := c.logging.start(req, p)
+	defer func() {
+		span.end(req.Context(), err)
+		log.end(err)
+	}()
 
-	resp, err := c.hc.Do(req)
+	resp, err := c.doer.Do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
+	span.response(resp)
+	log.response(resp)
 
 	switch resp.StatusCode {
-	case http.StatusNotFound:
-		return ErrNotFound
-	case http.StatusUnauthorized:
-		return ErrUnauthorized
-	case http.StatusForbidden:
-		return ErrForbidden
+	case http.StatusNotFound, http.StatusUnauthorized, http.StatusForbidden:
+		return NewAPIError(operation(req, p), resp)
 	}
 
 	err = rh(resp)
 	_, _ = io.Copy(io.Discard, resp.Body)
+
+	// handlers that do not name their operation get the one of the route
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Op == "" {
+		apiErr.Op = operation(req, p)
+	}
 	return err
 }
 
+// authenticatedSend adds the credentials and sends the request. Rejected credentials are refreshed and the request is
+// sent once more.
+func (c *Client) authenticatedSend(req *http.Request) (*http.Response, error) {
+	// the login request carries its own credentials
+	if c.credentials == nil || strings.HasSuffix(req.URL.Path, PathLogin) {
+		return c.send(req)
+	}
+
+	if err := c.credentials.Authenticate(req); err != nil {
+		return nil, fmt.Errorf("credentials: %w", err)
+	}
+
+	resp, err := c.send(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || !replayable(req) {
+		return resp, err
+	}
+
+	refresher, ok := c.credentials.(CredentialsRefresher)
+	if !ok || !refresher.Refresh(req) {
+		return resp, nil
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	if req, err = replay(req); err != nil {
+		return nil, err
+	}
+	if err = c.credentials.Authenticate(req); err != nil {
+		return nil, fmt.Errorf("credentials: %w", err)
+	}
+	return c.send(req)
+}
+
 func (c *Client) Repositories() *RepositoryClient {
 	return c.repository
 }
 
+func (c *Client) ACL() *AclClient {
+	return c.acl
+}
+
 func (c *Client) Backups() *BackupClient {
 	return c.backup
 }
@@ -141,6 +190,7 @@ func New(url string, opts ...Option) *Client {
 	}
 
 	client.repository = &RepositoryClient{client: client}
+	client.acl = &AclClient{client: client}
 	client.backup = &BackupClient{client: client}
 	client.cluster = &ClusterClient{client: client}
 	client.security = &SecurityClient{client: client}
@@ -154,5 +204,8 @@ func New(url string, opts ...Option) *Client {
 	for _, opt := range opts {
 		opt(client)
 	}
+
+	client.doer = client.chain(DoerFunc(client.route))
+	client.telemetry.observeCircuits(client.breakers)
 	return client
 }
@@ -0,0 +1,219 @@
+package graphdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+
+	"github.com/yaskoo/go-graphdb/rdf"
+)
+
+type CloneOptions struct {
+	// Config replaces the configuration read from the source repository. Its id is always set to the destination id.
+	Config *RepositoryConfig
+	// Location of the destination repository, the default location when empty.
+	Location string
+	// Format used to transfer statements. Defaults to rdf.FormatBinary.
+	Format rdf.Format
+	// SkipData creates the destination repository without copying any statements.
+	SkipData bool
+	// SkipNamespaces, SkipSparqlTemplates and SkipSqlViews skip copying the respective repository metadata.
+	SkipNamespaces      bool
+	SkipSparqlTemplates bool
+	SkipSqlViews        bool
+	// SavedQueries copies the saved queries missing on the destination server. Saved queries are not tied to
+	// a repository, so this is opt-in.
+	SavedQueries bool
+	// SkipVerify disables comparing the explicit statement count of both repositories after the copy.
+	SkipVerify bool
+}
+
+type CloneReport struct {
+	// Statements is the number of explicit statements in the destination repository. It is only counted when the
+	// copy is verified, so it stays 0 with SkipVerify.
+	Statements      int
+	Namespaces      int
+	SparqlTemplates int
+	SqlViews        int
+	SavedQueries    int
+}
+
+// Clone creates dstRepo on dst with the configuration of srcRepo on src, and copies its explicit statements and
+// metadata. Inferred statements are not transferred, the destination computes them from its own ruleset.
+// Both clients can point to the same server, or to different ones.
+// Clone does not clean up after a failure: once the destination repository is created, an error leaves it in place,
+// possibly with part of the data, and it must be deleted before cloning again.
+func Clone(ctx context.Context, src *Client, srcRepo string, dst *Client, dstRepo string, opts CloneOptions) (CloneReport, error) {
+	var report CloneReport
+	if opts.Format == "" {
+		opts.Format = rdf.FormatBinary
+	}
+
+	var config RepositoryConfig
+	if opts.Config != nil {
+		// the caller's config is left untouched, including its params
+		config = *opts.Config
+		config.Params = maps.Clone(opts.Config.Params)
+	} else {
+		c, err := src.repository.Config(ctx, srcRepo)
+		if err != nil {
+			return report, fmt.Errorf("clone: %w", err)
+		}
+		config = c
+	}
+
+	config.Id = dstRepo
+	config.Location = opts.Location
+	if p, ok := config.Params["id"]; ok {
+		p.Value = NewIntString(dstRepo)
+		config.Params["id"] = p
+	}
+
+	if err := dst.repository.Create(ctx, JsonBody(config)); err != nil {
+		return report, fmt.Errorf("clone: %w", err)
+	}
+
+	if !opts.SkipData {
+		if err := cloneStatements(ctx, src, srcRepo, dst, dstRepo, opts.Format); err != nil {
+			return report, err
+		}
+	}
+
+	if !opts.SkipNamespaces {
+		namespaces, err := src.rdf4j.Namespaces(ctx, srcRepo)
+		if err != nil {
+			return report, fmt.Errorf("clone: %w", err)
+		}
+
+		for prefix, namespace := range namespaces {
+			if err = dst.rdf4j.SetNamespace(ctx, dstRepo, prefix, namespace); err != nil {
+				return report, fmt.Errorf("clone: namespace %s: %w", prefix, err)
+			}
+			report.Namespaces++
+		}
+	}
+
+	if !opts.SkipSparqlTemplates {
+		ids, err := src.repository.SparqlTemplates(ctx, srcRepo)
+		if err != nil {
+			return report, fmt.Errorf("clone: %w", err)
+		}
+
+		for _, id := range ids {
+			template, err := src.repository.SparqlTemplate(ctx, srcRepo, id)
+			if err != nil {
+				return report, fmt.Errorf("clone: sparql template %s: %w", id, err)
+			}
+
+			if err = dst.repository.CreateSparqlTemplates(ctx, dstRepo, template); err != nil {
+				return report, fmt.Errorf("clone: sparql template %s: %w", id, err)
+			}
+			report.SparqlTemplates++
+		}
+	}
+
+	if !opts.SkipSqlViews {
+		names, err := src.repository.SqlViews(ctx, srcRepo)
+		if err != nil {
+			return report, fmt.Errorf("clone: %w", err)
+		}
+
+		for _, name := range names {
+			view, err := src.repository.SqlView(ctx, srcRepo, name)
+			if err != nil {
+				return report, fmt.Errorf("clone: sql view %s: %w", name, err)
+			}
+
+			if err = dst.repository.CreateSqlView(ctx, dstRepo, view); err != nil {
+				return report, fmt.Errorf("clone: sql view %s: %w", name, err)
+			}
+			report.SqlViews++
+		}
+	}
+
+	if opts.SavedQueries {
+		n, err := cloneSavedQueries(ctx, src, dst)
+		report.SavedQueries = n
+		if err != nil {
+			return report, err
+		}
+	}
+
+	if opts.SkipVerify {
+		return report, nil
+	}
+
+	srcSize, err := src.repository.Size(ctx, srcRepo)
+	if err != nil {
+		return report, fmt.Errorf("clone: %w", err)
+	}
+
+	dstSize, err := dst.repository.Size(ctx, dstRepo)
+	if err != nil {
+		return report, fmt.Errorf("clone: %w", err)
+	}
+	report.Statements = dstSize.Explicit
+
+	expected := srcSize.Explicit
+	if opts.SkipData {
+		expected = 0
+	}
+
+	if dstSize.Explicit != expected {
+		return report, fmt.Errorf("clone: expected %d explicit statements in %s, found %d", expected, dstRepo, dstSize.Explicit)
+	}
+	return report, nil
+}
+
+// cloneStatements pipes the explicit statements of the source repository straight into the destination one.
+func cloneStatements(ctx context.Context, src *Client, srcRepo string, dst *Client, dstRepo string, format rdf.Format) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pr, pw := io.Pipe()
+	go func() {
+		err := src.rdf4j.Statements(ctx, srcRepo, format, func(r io.Reader) error {
+			_, err := io.Copy(pw, r)
+			return err
+		}, Query("infer", "false"))
+		_ = pw.CloseWithError(err)
+	}()
+
+	err := dst.rdf4j.AddStatements(ctx, dstRepo, format, pr)
+	_ = pr.CloseWithError(err)
+	if err != nil {
+		return fmt.Errorf("clone: statements: %w", err)
+	}
+	return nil
+}
+
+func cloneSavedQueries(ctx context.Context, src *Client, dst *Client) (int, error) {
+	queries, err := src.savedQueries.SavedQueries(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("clone: %w", err)
+	}
+
+	existing, err := dst.savedQueries.SavedQueries(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("clone: %w", err)
+	}
+
+	names := map[string]bool{}
+	for _, q := range existing {
+		names[q.Name] = true
+	}
+
+	var n int
+	for _, q := range queries {
+		if names[q.Name] {
+			continue
+		}
+
+		if err = dst.savedQueries.CreateSavedQueries(ctx, q); err != nil {
+			return n, fmt.Errorf("clone: saved query %s: %w", q.Name, err)
+		}
+		n++
+	}
+	return n, nil
+}
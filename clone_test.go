@@ -0,0 +1,90 @@
+package graphdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/yaskoo/go-graphdb/testenv"
+)
+
+func TestClone(t *testing.T) {
+	testenv.WithEnv(t, func(url string) {
+		client := New(url)
+		ctx := context.Background()
+
+		src, err := createRepository(t, client)
+		if err != nil {
+			t.Fatalf("failed to create repository: %v", err)
+		}
+
+		data := `<http://example.org/s> <http://example.org/p> "o" .`
+		if err = client.Repositories().ImportText(ctx, src, data, ImportSettings{Name: "snippet", Format: "text/turtle"}); err != nil {
+			t.Fatalf("failed to import text: %v", err)
+		}
+
+		if _, err = client.Repositories().WaitImport(ctx, src, "snippet"); err != nil {
+			t.Fatalf("failed to wait for import: %v", err)
+		}
+
+		if err = client.RDF4J().SetNamespace(ctx, src, "ex", "http://example.org/"); err != nil {
+			t.Fatalf("failed to set namespace: %v", err)
+		}
+
+		dst := uuid.New().String()
+		report, err := Clone(ctx, client, src, client, dst, CloneOptions{})
+		if err != nil {
+			t.Fatalf("failed to clone repository: %v", err)
+		}
+
+		if report.Statements != 1 {
+			t.Errorf("expected 1 cloned statement, got %d", report.Statements)
+		}
+
+		namespaces, err := client.RDF4J().Namespaces(ctx, dst)
+		if err != nil || namespaces["ex"] != "http://example.org/" {
+			t.Errorf("expected cloned namespace, got %v, %v", namespaces, err)
+		}
+	})
+}
+
+func TestClone_KeepsConfig(t *testing.T) {
+	var created RepositoryConfig
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != PathRepositories {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&created)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	config := &RepositoryConfig{Id: "template", Params: map[string]RepositoryConfigParameter{
+		"id": {Name: "id", Value: NewIntString("template")},
+	}}
+
+	client := New(server.URL)
+	_, err := Clone(context.Background(), client, "src", client, "dst", CloneOptions{
+		Config:              config,
+		Location:            "http://remote:7200",
+		SkipData:            true,
+		SkipNamespaces:      true,
+		SkipSparqlTemplates: true,
+		SkipSqlViews:        true,
+		SkipVerify:          true,
+	})
+	if err != nil {
+		t.Fatalf("failed to clone repository: %v", err)
+	}
+
+	if created.Id != "dst" || created.Params["id"].Value.String() != "dst" || created.Location != "http://remote:7200" {
+		t.Errorf("unexpected created config: %+v", created)
+	}
+	if config.Id != "template" || config.Params["id"].Value.String() != "template" || config.Location != "" {
+		t.Errorf("expected the config of the options to be left untouched, got %+v", config)
+	}
+}
@@ -34,24 +34,6 @@ const (
 	ClusterDisableSecondary = "cluster_disable_secondary"
 )
 
-type ClusterError struct {
-	Op       string
-	NotFound bool
-	Messages StringSliceMap
-	Err      error
-}
-
-func (c ClusterError) Error() string {
-	if c.Err != nil {
-		return fmt.Sprintf("%s: %s", c.Op, c.Err.Error())
-	}
-	return c.Op
-}
-
-func (c ClusterError) Unwrap() error {
-	return c.Err
-}
-
 type ClusterProperties struct {
 	ElectionMinTimeout          int     `json:"electionMinTimeout,omitempty"`
 	ElectionRangeTimeout        int     `json:"electionRangeTimeout,omitempty"`
@@ -253,34 +235,31 @@ func (c *ClusterClient) EnableSecondaryMode(ctx context.Context, primary, tag st
 
 func (c *ClusterClient) DisableSecondaryMode(ctx context.Context, config ...RequestConfig) error {
 	rh := clusterResponseHandler(ClusterDisableSecondary, http.StatusOK, nil)
-	return c.client.post(ctx, PathClusterSecondaryMode, nil, rh, config...)
+	return c.client.delete(ctx, PathClusterSecondaryMode, nil, rh, config...)
+}
+
+// ClusterMessages returns the messages of a failed cluster operation, either a list or a message per node.
+func ClusterMessages(err *APIError) *StringSliceMap {
+	var messages StringSliceMap
+	data, _ := json.Marshal(err.Body)
+	_ = json.Unmarshal(data, &messages)
+	return &messages
 }
 
+// clusterResponseHandler decodes the response into re on okStatus, failed operations return an *APIError.
 func clusterResponseHandler(op string, okStatus int, re any) ResponseHandler {
 	return func(resp *http.Response) error {
-		if resp.StatusCode == okStatus {
-			if re == nil {
-				return nil
-			}
-
-			err := json.NewDecoder(resp.Body).Decode(&re)
-			if err != nil {
-				return ClusterError{
-					Op:  op,
-					Err: err,
-				}
-			}
-			return nil
+		if err := ErrNotStatus(okStatus, op, resp); err != nil {
+			return err
 		}
 
-		clusterErr := ClusterError{
-			Op:       op,
-			NotFound: resp.StatusCode == http.StatusNotFound,
+		if re == nil {
+			return nil
 		}
-		err := json.NewDecoder(resp.Body).Decode(&clusterErr.Messages)
-		if err != nil {
-			clusterErr.Err = err
+
+		if err := json.NewDecoder(resp.Body).Decode(&re); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
 		}
-		return clusterErr
+		return nil
 	}
 }
@@ -4,8 +4,8 @@ package graphdb
 //	client := New("http://first.127.0.0.1.nip.io:7201")
 //
 //	_, err := client.Cluster().Config(context.Background())
-//	var ce ClusterError
-//	if !errors.As(err, &ce) || !ce.NotFound {
+//	var ce *APIError
+//	if !errors.As(err, &ce) || !errors.Is(err, ErrNotFound) {
 //		t.Errorf("expected no cluster error, got: %v", err)
 //	}
 //
@@ -13,13 +13,13 @@ package graphdb
 //		Nodes: []string{"graphdb.example.com"},
 //	})
 //
-//	if !errors.As(err, &ce) || !ce.Messages.IsList() || len(ce.Messages.List()) != 2 {
-//		t.Errorf("expected two error messages, got %v", ce.Messages.List())
+//	if !errors.As(err, &ce) || !ClusterMessages(ce).IsList() || len(ClusterMessages(ce).List()) != 2 {
+//		t.Errorf("expected two error messages, got %v", ClusterMessages(ce).List())
 //	}
 //
 //	err = client.Cluster().Truncate(context.Background())
 //
-//	if !errors.As(err, &ce) || len(ce.Messages.List()) != 1 {
+//	if !errors.As(err, &ce) || len(ClusterMessages(ce).List()) != 1 {
 //		t.Errorf("expected truncate log to fail because there is no cluster")
 //	}
 //
@@ -30,22 +30,22 @@ package graphdb
 //		},
 //	})
 //
-//	if !errors.As(err, &ce) || len(ce.Messages.Map()) != 1 {
-//		t.Errorf("expected one error message, got %v", ce.Messages.List())
+//	if !errors.As(err, &ce) || len(ClusterMessages(ce).Map()) != 1 {
+//		t.Errorf("expected one error message, got %v", ClusterMessages(ce).List())
 //	}
 //
 //	_, err = client.Cluster().AddNodes(context.Background(), []string{"second.127.0.0.1.nip.io:7302"})
-//	if !errors.As(err, &ce) || ce.Messages.IsList() == false || len(ce.Messages.List()) != 1 {
+//	if !errors.As(err, &ce) || ClusterMessages(ce).IsList() == false || len(ClusterMessages(ce).List()) != 1 {
 //		t.Error("expected add nodes to fail, because there is no cluster")
 //	}
 //
 //	_, err = client.Cluster().DeleteNodes(context.Background(), []string{"second.127.0.0.1.nip.io:7302"})
-//	if !errors.As(err, &ce) || ce.Messages.IsList() == false || len(ce.Messages.List()) != 1 {
+//	if !errors.As(err, &ce) || ClusterMessages(ce).IsList() == false || len(ClusterMessages(ce).List()) != 1 {
 //		t.Error("expected delete nodes to fail, because there is no cluster")
 //	}
 //
 //	_, err = client.Cluster().ReplaceNodes(context.Background(), []string{"fourth.127.0.0.1.nip.io:7304"}, []string{"second.127.0.0.1.nip.io:7302"})
-//	if !errors.As(err, &ce) || ce.Messages.IsList() == false || len(ce.Messages.List()) != 1 {
+//	if !errors.As(err, &ce) || ClusterMessages(ce).IsList() == false || len(ClusterMessages(ce).List()) != 1 {
 //		t.Error("expected replace nodes to fail, because there is no cluster")
 //	}
 //
@@ -0,0 +1,54 @@
+package graphdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// AfterIndex makes a cluster client send a read to a follower only once its last log index reaches index, usually
+// the one returned by ClusterClient.LeaderIndex after a write, so that the read sees the write. Reads pinned with
+// OnNode and clients not created with NewClusterClient ignore it.
+func AfterIndex(index int) RequestConfig {
+	return func(req *http.Request) {
+		*req = *req.WithContext(context.WithValue(req.Context(), indexKey{}, index))
+	}
+}
+
+type indexKey struct{}
+
+// LeaderIndex returns the last log index of the leader. Once a follower reaches it, the follower has applied all
+// writes completed before the call.
+func (c *ClusterClient) LeaderIndex(ctx context.Context, config ...RequestConfig) (int, error) {
+	if r := c.client.router; r != nil {
+		leader, err := r.pick(ctx, true)
+		if err != nil {
+			return 0, err
+		}
+		config = append(config, OnNode(leader))
+	}
+
+	status, err := c.NodeStatus(ctx, config...)
+	return status.LastLogIndex, err
+}
+
+// WaitIndex polls the status of the node until its last log index reaches index, or the context is done.
+// The poll interval and backoff are configured with WithPolling.
+func (c *ClusterClient) WaitIndex(ctx context.Context, node string, index int, config ...RequestConfig) error {
+	config = append(config, OnNode(node))
+
+	p := newPoller(c.client.polling)
+	for {
+		status, err := c.NodeStatus(ctx, config...)
+		if err != nil {
+			return err
+		}
+		if status.LastLogIndex >= index {
+			return nil
+		}
+
+		if err = p.wait(ctx); err != nil {
+			return fmt.Errorf("%s: waiting for %s to reach index %d, last index %d: %w", ClusterNodeStatus, node, index, status.LastLogIndex, err)
+		}
+	}
+}
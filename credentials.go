@@ -0,0 +1,133 @@
+package graphdb
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CredentialsProvider authenticates requests. It is consulted before every request, so it can pick up rotated
+// secrets, and must be safe for concurrent use.
+type CredentialsProvider interface {
+	Authenticate(req *http.Request) error
+}
+
+// CredentialsRefresher is implemented by providers whose credentials can be renewed. Refresh is called when the server
+// rejects a request with 401, and the request is authenticated and sent once more when it returns true.
+type CredentialsRefresher interface {
+	Refresh(req *http.Request) bool
+}
+
+// WithCredentials authenticates all requests with the provider.
+func WithCredentials(provider CredentialsProvider) Option {
+	return func(client *Client) {
+		client.credentials = provider
+	}
+}
+
+// WithBasicAuth authenticates all requests with fixed basic auth credentials.
+func WithBasicAuth(username, password string) Option {
+	return WithCredentials(BasicCredentials(StaticSecret(username), StaticSecret(password)))
+}
+
+// Secret returns the current value of a secret.
+type Secret func() (string, error)
+
+// StaticSecret returns a secret that never changes.
+func StaticSecret(value string) Secret {
+	return func() (string, error) {
+		return value, nil
+	}
+}
+
+// EnvSecret reads the secret from an environment variable on every use.
+func EnvSecret(name string) Secret {
+	return func() (string, error) {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("secret: environment variable %s is not set", name)
+		}
+		return value, nil
+	}
+}
+
+// FileSecret reads the secret from a file, e.g. a mounted Kubernetes secret, and reads it again when the file changes.
+// Surrounding whitespace is trimmed.
+func FileSecret(path string) Secret {
+	var mu sync.Mutex
+	var value string
+	var modTime time.Time
+	var size int64 = -1
+
+	return func() (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", fmt.Errorf("secret: %w", err)
+		}
+
+		if info.ModTime().Equal(modTime) && info.Size() == size {
+			return value, nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("secret: %w", err)
+		}
+		value, modTime, size = strings.TrimSpace(string(data)), info.ModTime(), info.Size()
+		return value, nil
+	}
+}
+
+// BasicCredentials authenticates with basic auth, reading the username and password on every request.
+func BasicCredentials(username, password Secret) CredentialsProvider {
+	return &basicCredentials{username: username, password: password}
+}
+
+type basicCredentials struct {
+	username Secret
+	password Secret
+}
+
+func (b *basicCredentials) Authenticate(req *http.Request) error {
+	username, err := b.username()
+	if err != nil {
+		return err
+	}
+
+	password, err := b.password()
+	if err != nil {
+		return err
+	}
+
+	req.SetBasicAuth(username, password)
+	return nil
+}
+
+// BearerCredentials authenticates with a bearer token read on every request.
+func BearerCredentials(token Secret) CredentialsProvider {
+	return bearerCredentials{token: token}
+}
+
+type bearerCredentials struct {
+	token Secret
+}
+
+func (b bearerCredentials) Authenticate(req *http.Request) error {
+	token, err := b.token()
+	if err != nil {
+		return err
+	}
+
+	if token == "" {
+		return errors.New("secret: empty bearer token")
+	}
+	req.Header.Set("authorization", "Bearer "+token)
+	return nil
+}
@@ -0,0 +1,157 @@
+package graphdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCredentials_Rotation(t *testing.T) {
+	var authorization atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization.Store(r.Header.Get("authorization"))
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "password")
+	if err := os.WriteFile(passwordFile, []byte("first\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GRAPHDB_TEST_USERNAME", "admin")
+
+	ctx := context.Background()
+	client := New(server.URL, WithCredentials(BasicCredentials(EnvSecret("GRAPHDB_TEST_USERNAME"), FileSecret(passwordFile))))
+
+	if _, err := client.Security().Users(ctx); err != nil {
+		t.Fatalf("failed to list users: %v", err)
+	}
+
+	req := &http.Request{Header: http.Header{}}
+	req.SetBasicAuth("admin", "first")
+	if authorization.Load() != req.Header.Get("authorization") {
+		t.Errorf("unexpected authorization %v", authorization.Load())
+	}
+
+	// a rotated secret is picked up by the next request
+	if err := os.WriteFile(passwordFile, []byte("second-password\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Security().Users(ctx); err != nil {
+		t.Fatalf("failed to list users: %v", err)
+	}
+
+	req.SetBasicAuth("admin", "second-password")
+	if authorization.Load() != req.Header.Get("authorization") {
+		t.Errorf("expected the rotated password, got %v", authorization.Load())
+	}
+
+	client = New(server.URL, WithCredentials(BasicCredentials(EnvSecret("GRAPHDB_TEST_MISSING"), StaticSecret(""))))
+	if _, err := client.Security().Users(ctx); err == nil {
+		t.Error("expected a missing environment variable to fail the request")
+	}
+}
+
+func TestCredentials_OAuth2ClientCredentials(t *testing.T) {
+	var issued atomic.Int32
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/realms/graphdb/.well-known/openid-configuration":
+			_ = json.NewEncoder(w).Encode(map[string]string{"token_endpoint": "http://" + r.Host + "/realms/graphdb/token"})
+		case "/realms/graphdb/token":
+			id, secret, _ := r.BasicAuth()
+			if r.PostFormValue("grant_type") != "client_credentials" || r.PostFormValue("scope") != "graphdb" || id != "client" || secret != "s3cret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			n := issued.Add(1)
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": fmt.Sprintf("token-%d", n), "token_type": "Bearer", "expires_in": 3600})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer idp.Close()
+
+	var rejected atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("authorization") == "Bearer token-1" && rejected.Load() {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("authorization") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := New(server.URL, WithCredentials(OAuth2ClientCredentials(OAuth2Config{
+		Issuer:       idp.URL + "/realms/graphdb",
+		ClientID:     "client",
+		ClientSecret: StaticSecret("s3cret"),
+		Scopes:       []string{"graphdb"},
+	})))
+
+	for range 3 {
+		if _, err := client.Security().Users(ctx); err != nil {
+			t.Fatalf("failed to list users: %v", err)
+		}
+	}
+
+	if n := issued.Load(); n != 1 {
+		t.Errorf("expected the token to be cached, got %d tokens", n)
+	}
+
+	// a revoked token is replaced
+	rejected.Store(true)
+	if _, err := client.Security().Users(ctx); err != nil {
+		t.Fatalf("failed to list users after token revocation: %v", err)
+	}
+
+	if n := issued.Load(); n != 2 {
+		t.Errorf("expected a new token, got %d tokens", n)
+	}
+
+	client = New(server.URL, WithCredentials(OAuth2ClientCredentials(OAuth2Config{
+		TokenURL:     idp.URL + "/realms/graphdb/token",
+		ClientID:     "client",
+		ClientSecret: StaticSecret("wrong"),
+		Scopes:       []string{"graphdb"},
+	})))
+
+	if _, err := client.Security().Users(ctx); err == nil {
+		t.Error("expected invalid client credentials to fail")
+	}
+}
+
+func TestCredentials_OAuth2Expiry(t *testing.T) {
+	var issued atomic.Int32
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issued.Add(1)
+		// tokens expiring within the refresh margin are fetched again on each request
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "token", "expires_in": int((30 * time.Second).Seconds())})
+	}))
+	defer idp.Close()
+
+	provider := OAuth2ClientCredentials(OAuth2Config{TokenURL: idp.URL, ClientID: "client", ClientSecret: StaticSecret("s3cret")})
+	for range 2 {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if err := provider.Authenticate(req); err != nil || req.Header.Get("authorization") != "Bearer token" {
+			t.Fatalf("failed to authenticate: %v", err)
+		}
+	}
+
+	if n := issued.Load(); n != 2 {
+		t.Errorf("expected a token per request, got %d", n)
+	}
+}
@@ -0,0 +1,257 @@
+package graphdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	DriftOnlyLeft  = "only_left"
+	DriftOnlyRight = "only_right"
+	DriftChanged   = "changed"
+)
+
+const (
+	queryListPlugins    = `SELECT * WHERE { ?plugin <http://www.ontotext.com/owlim/system#listplugins> ?state }`
+	queryListConnectors = `SELECT ?cntUri ?cntStr WHERE { ?cntUri <http://www.ontotext.com/connectors/%s#listConnectors> ?cntStr }`
+)
+
+// ConnectorTypes are the GraphDB connector types compared by Drift.
+var ConnectorTypes = []string{"lucene", "elasticsearch", "opensearch", "solr", "kafka"}
+
+// Difference is a single setting that differs between two servers. Keys are paths such as
+// "repository/<id>/param/ruleset" or "user/<name>/authorities".
+type Difference struct {
+	Key   string `json:"key"`
+	Kind  string `json:"kind"`
+	Left  string `json:"left,omitempty"`
+	Right string `json:"right,omitempty"`
+}
+
+func (d Difference) String() string {
+	switch d.Kind {
+	case DriftOnlyLeft:
+		return fmt.Sprintf("- %s: %q", d.Key, d.Left)
+	case DriftOnlyRight:
+		return fmt.Sprintf("+ %s: %q", d.Key, d.Right)
+	}
+	return fmt.Sprintf("~ %s: %q -> %q", d.Key, d.Left, d.Right)
+}
+
+type DriftReport struct {
+	Left        string       `json:"left"`
+	Right       string       `json:"right"`
+	Differences []Difference `json:"differences"`
+}
+
+// Drifted reports whether any difference was found.
+func (r DriftReport) Drifted() bool {
+	return len(r.Differences) > 0
+}
+
+// String renders the report as a diff from the left to the right server, one difference per line.
+func (r DriftReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", r.Left, r.Right)
+	if !r.Drifted() {
+		sb.WriteString("no differences\n")
+	}
+
+	for _, d := range r.Differences {
+		sb.WriteString(d.String())
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// Drift compares the configuration of two servers: repositories and their configs, SPARQL templates, SQL views, ACLs,
+// plugins and connectors, security and free access settings, users, custom roles, locations, saved queries and cluster
+// properties. Repository data and cluster node addresses are not compared.
+func Drift(ctx context.Context, left, right *Client) (DriftReport, error) {
+	report := DriftReport{Left: left.url, Right: right.url}
+
+	l, err := left.driftSnapshot(ctx)
+	if err != nil {
+		return report, fmt.Errorf("drift: %s: %w", left.url, err)
+	}
+
+	r, err := right.driftSnapshot(ctx)
+	if err != nil {
+		return report, fmt.Errorf("drift: %s: %w", right.url, err)
+	}
+
+	keys := slices.Sorted(maps.Keys(l))
+	for k := range r {
+		if _, ok := l[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		lv, inLeft := l[k]
+		rv, inRight := r[k]
+		switch {
+		case !inRight:
+			report.Differences = append(report.Differences, Difference{Key: k, Kind: DriftOnlyLeft, Left: lv})
+		case !inLeft:
+			report.Differences = append(report.Differences, Difference{Key: k, Kind: DriftOnlyRight, Right: rv})
+		case lv != rv:
+			report.Differences = append(report.Differences, Difference{Key: k, Kind: DriftChanged, Left: lv, Right: rv})
+		}
+	}
+	return report, nil
+}
+
+// driftSnapshot flattens the server configuration into comparable key/value pairs.
+func (c *Client) driftSnapshot(ctx context.Context) (map[string]string, error) {
+	state, err := c.currentState(ctx, environmentOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	s := map[string]string{}
+	for _, repo := range state.Repositories {
+		prefix := path.Join("repository", repo.Config.Id)
+		s[prefix] = repo.Config.Type
+		s[prefix+"/title"] = repo.Config.Title
+		for name, param := range repo.Config.Params {
+			if name != "id" {
+				s[prefix+"/param/"+name] = param.Value.String()
+			}
+		}
+
+		for _, template := range repo.SparqlTemplates {
+			s[prefix+"/sparql_template/"+template.Id] = strings.TrimSpace(template.Query)
+		}
+
+		for _, view := range repo.SqlViews {
+			s[prefix+"/sql_view/"+view.Name] = jsonString(view)
+		}
+
+		if repo.Acl != nil && len(*repo.Acl) > 0 {
+			s[prefix+"/acl"] = jsonString(*repo.Acl)
+		}
+
+		if err = c.driftPlugins(ctx, repo.Config.Id, prefix, s); err != nil {
+			return nil, fmt.Errorf("repository %s: %w", repo.Config.Id, err)
+		}
+	}
+
+	enabled, err := c.security.Enabled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s["security/enabled"] = strconv.FormatBool(enabled)
+
+	if free := state.FreeAccess; free != nil {
+		s["free_access/enabled"] = strconv.FormatBool(free.Enabled)
+		s["free_access/authorities"] = strings.Join(sorted(free.Authorities), ",")
+		for k, v := range free.AppSettings {
+			s["free_access/appSettings/"+k] = strconv.FormatBool(v)
+		}
+	}
+
+	for _, user := range state.Users {
+		s["user/"+user.Username+"/authorities"] = strings.Join(sorted(user.GrantedAuthorities), ",")
+		for k, v := range user.AppSettings {
+			s["user/"+user.Username+"/appSettings/"+k] = strconv.FormatBool(v)
+		}
+	}
+
+	for role, users := range state.CustomRoles {
+		s["custom_role/"+role] = strings.Join(sorted(users), ",")
+	}
+
+	for _, location := range state.Locations {
+		prefix := "location/" + location.Uri
+		s[prefix+"/label"] = location.Label
+		s[prefix+"/authType"] = location.AuthType
+		s[prefix+"/username"] = location.Username
+		s[prefix+"/locationType"] = location.LocationType
+		s[prefix+"/defaultRepository"] = location.DefaultRepository
+	}
+
+	for _, query := range state.SavedQueries {
+		s["saved_query/"+query.Name+"/body"] = strings.TrimSpace(query.Body)
+		s["saved_query/"+query.Name+"/shared"] = strconv.FormatBool(query.Shared)
+	}
+
+	return s, c.driftCluster(ctx, s)
+}
+
+func (c *Client) driftPlugins(ctx context.Context, repo, prefix string, s map[string]string) error {
+	plugins, err := c.rdf4j.Select(ctx, repo, queryListPlugins)
+	if err != nil {
+		return err
+	}
+
+	for _, plugin := range plugins {
+		s[prefix+"/plugin/"+path.Base(plugin["plugin"])] = plugin["state"]
+	}
+
+	for _, connectorType := range ConnectorTypes {
+		connectors, err := c.rdf4j.Select(ctx, repo, fmt.Sprintf(queryListConnectors, connectorType))
+		if connectorUnavailable(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, connector := range connectors {
+			name := connector["cntUri"]
+			if i := strings.LastIndex(name, "#"); i >= 0 {
+				name = name[i+1:]
+			}
+			s[prefix+"/connector/"+connectorType+"/"+name] = connector["cntStr"]
+		}
+	}
+	return nil
+}
+
+// connectorUnavailable reports whether the connector query failed because the connector type is not available on the
+// server, which rejects its predicates as a bad request or as unknown.
+func connectorUnavailable(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusBadRequest || strings.Contains(strings.ToLower(apiErr.Message), "unknown predicate")
+}
+
+func (c *Client) driftCluster(ctx context.Context, s map[string]string) error {
+	config, err := c.cluster.Config(ctx)
+	if errors.Is(err, ErrNotFound) {
+		s["cluster/enabled"] = "false"
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+	s["cluster/enabled"] = "true"
+
+	var props map[string]any
+	_ = json.Unmarshal([]byte(jsonString(config.ClusterProperties)), &props)
+	for k, v := range props {
+		s["cluster/"+k] = fmt.Sprint(v)
+	}
+	return nil
+}
+
+func jsonString(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
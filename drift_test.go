@@ -0,0 +1,97 @@
+package graphdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func driftServer(ruleset string, roles map[string][]string, plugins string, connectorStatus int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var v any
+		switch r.URL.Path {
+		case PathRepositories:
+			v = []RepositoryInfo{{Id: "repo"}}
+		case "/rest/repositories/repo":
+			v = map[string]any{"id": "repo", "type": "graphdb", "params": map[string]any{
+				"ruleset": map[string]any{"name": "ruleset", "value": ruleset},
+			}}
+		case "/repositories/repo":
+			if r.Header.Get("content-type") != "application/sparql-query" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			query, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(query), "listplugins") {
+				w.WriteHeader(connectorStatus)
+				return
+			}
+			_, _ = w.Write([]byte(plugins))
+			return
+		case "/rest/repositories/repo/sparql-templates", PathSqlViews, "/rest/repositories/repo/acl", PathLocations, PathSavedQueries, PathSecurityUsers:
+			v = []string{}
+		case PathCustomRoles:
+			v = roles
+		case PathSecurity:
+			v = false
+		case PathSecurityFreeAccess:
+			v = FreeAccess{}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(v)
+	}))
+}
+
+func TestDrift(t *testing.T) {
+	left := driftServer("owl2-rl", map[string][]string{"custom_a": {"x"}}, `{"results":{"bindings":[{"plugin":{"value":"http://www.ontotext.com/plugins/rdfrank"},"state":{"value":"active"}}]}}`, http.StatusBadRequest)
+	defer left.Close()
+
+	right := driftServer("rdfsplus-optimized", map[string][]string{"custom_b": {"x"}}, `{"results":{"bindings":[{"plugin":{"value":"http://www.ontotext.com/plugins/rdfrank"},"state":{"value":"inactive"}}]}}`, http.StatusBadRequest)
+	defer right.Close()
+
+	report, err := Drift(context.Background(), New(left.URL), New(right.URL))
+	if err != nil {
+		t.Fatalf("failed to compute drift: %v", err)
+	}
+
+	expected := []Difference{
+		{Key: "custom_role/custom_a", Kind: DriftOnlyLeft, Left: "x"},
+		{Key: "custom_role/custom_b", Kind: DriftOnlyRight, Right: "x"},
+		{Key: "repository/repo/param/ruleset", Kind: DriftChanged, Left: "owl2-rl", Right: "rdfsplus-optimized"},
+		{Key: "repository/repo/plugin/rdfrank", Kind: DriftChanged, Left: "active", Right: "inactive"},
+	}
+
+	if len(report.Differences) != len(expected) {
+		t.Fatalf("unexpected differences:\n%s", report)
+	}
+
+	for i, d := range expected {
+		if report.Differences[i] != d {
+			t.Errorf("expected %v, got %v", d, report.Differences[i])
+		}
+	}
+
+	if !strings.Contains(report.String(), `~ repository/repo/param/ruleset: "owl2-rl" -> "rdfsplus-optimized"`) {
+		t.Errorf("unexpected text report:\n%s", report)
+	}
+}
+
+func TestDrift_ConnectorErrors(t *testing.T) {
+	left := driftServer("owl2-rl", nil, `{"results":{"bindings":[]}}`, http.StatusBadRequest)
+	defer left.Close()
+
+	right := driftServer("owl2-rl", nil, `{"results":{"bindings":[]}}`, http.StatusUnauthorized)
+	defer right.Close()
+
+	if _, err := Drift(context.Background(), New(left.URL), New(right.URL)); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected the failed connector check to be reported, got %v", err)
+	}
+}
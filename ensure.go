@@ -0,0 +1,72 @@
+package graphdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// EnsureRepository creates the repository if it is missing, or updates its title and the params set in config when
+// they differ. The returned plan holds the applied changes and is empty when the repository already matched.
+func (c *Client) EnsureRepository(ctx context.Context, config RepositoryConfig) (Plan, error) {
+	return c.ensure(ctx, c.planRepositories, State{Repositories: []RepositoryState{{Config: config}}})
+}
+
+// EnsureUser creates the user if it is missing, or updates its authorities and app settings. Passwords are only set
+// on creation.
+func (c *Client) EnsureUser(ctx context.Context, user User) (Plan, error) {
+	return c.ensure(ctx, c.planUsers, State{Users: []User{user}})
+}
+
+// EnsureCustomRole assigns the custom role to exactly the given users, creating the role when it is missing. The
+// other custom roles are left untouched.
+func (c *Client) EnsureCustomRole(ctx context.Context, role string, users []string) (Plan, error) {
+	return c.ensure(ctx, func(ctx context.Context, _ State) (Plan, error) {
+		current, err := c.security.GetCustomRoleUsers(ctx, role)
+		if errors.Is(err, ErrNotFound) {
+			return Plan{{Action: ChangeCreate, Resource: ResourceCustomRoles, Name: role, apply: func(ctx context.Context) error {
+				return c.security.AddCustomRoleUsers(ctx, role, users)
+			}}}, nil
+		}
+		if err != nil || sameSet(current, users) {
+			return nil, err
+		}
+
+		diff := []string{fmt.Sprintf("%s: %v -> %v", role, sorted(current), sorted(users))}
+		return Plan{{Action: ChangeUpdate, Resource: ResourceCustomRoles, Name: role, Diff: diff, apply: func(ctx context.Context) error {
+			return c.security.ReplaceCustomRoleUsers(ctx, role, users)
+		}}}, nil
+	}, State{})
+}
+
+// EnsureLocation attaches the location if it is missing, or updates it when it differs.
+func (c *Client) EnsureLocation(ctx context.Context, location Location) (Plan, error) {
+	return c.ensure(ctx, c.planLocations, State{Locations: []Location{location}})
+}
+
+// EnsureSavedQuery creates the saved query if it is missing, or updates its body and sharing.
+func (c *Client) EnsureSavedQuery(ctx context.Context, query SavedQuery) (Plan, error) {
+	return c.ensure(ctx, c.planSavedQueries, State{SavedQueries: []SavedQuery{query}})
+}
+
+// EnsureSparqlTemplate creates the SPARQL template in the repository if it is missing, or updates its query.
+func (c *Client) EnsureSparqlTemplate(ctx context.Context, repo string, template SparqlTemplate) (Plan, error) {
+	return c.ensure(ctx, func(ctx context.Context, _ State) (Plan, error) {
+		return c.planSparqlTemplates(ctx, repo, []SparqlTemplate{template})
+	}, State{})
+}
+
+// ensure plans a single step and applies its changes.
+func (c *Client) ensure(ctx context.Context, step func(context.Context, State) (Plan, error), desired State) (Plan, error) {
+	plan, err := step(ctx, desired)
+	if err != nil {
+		return nil, fmt.Errorf("ensure: %w", err)
+	}
+
+	for i, change := range plan {
+		if err = change.apply(ctx); err != nil {
+			return plan[:i], fmt.Errorf("ensure: %s: %w", change, err)
+		}
+	}
+	return plan, nil
+}
@@ -0,0 +1,97 @@
+package graphdb
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_Ensure(t *testing.T) {
+	var applied []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			body, _ := io.ReadAll(r.Body)
+			applied = append(applied, r.Method+" "+r.URL.Path+" "+string(body))
+			if r.Method == http.MethodPost && !strings.HasPrefix(r.URL.Path, PathCustomRoles) {
+				w.WriteHeader(http.StatusCreated)
+			}
+			return
+		}
+
+		var v any
+		switch r.URL.Path {
+		case PathRepositories:
+			v = []RepositoryInfo{{Id: "existing"}}
+		case "/rest/repositories/existing":
+			v = map[string]any{"id": "existing", "title": "Existing", "params": map[string]any{}}
+		case "/rest/repositories/existing/sparql-templates":
+			v = []string{"http://example.org/template"}
+		case "/rest/repositories/existing/sparql-templates/configuration":
+			v = SparqlTemplate{Id: "http://example.org/template", Query: "DELETE WHERE { ?s ?p ?o }"}
+		case "/rest/security/custom-roles/custom_readers":
+			v = []string{"reader"}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(v)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := New(server.URL)
+
+	plan, err := client.EnsureRepository(ctx, RepositoryConfig{Id: "existing", Title: "Existing"})
+	if err != nil || len(plan) != 0 {
+		t.Fatalf("expected no changes for a matching repository, got %v: %v", plan, err)
+	}
+
+	plan, err = client.EnsureRepository(ctx, RepositoryConfig{Id: "missing", Type: "graphdb"})
+	if err != nil || len(plan) != 1 || plan[0].Action != ChangeCreate {
+		t.Fatalf("expected the repository to be created, got %v: %v", plan, err)
+	}
+
+	plan, err = client.EnsureSparqlTemplate(ctx, "existing", SparqlTemplate{Id: "http://example.org/template", Query: "DELETE WHERE { ?s ?p ?o }"})
+	if err != nil || len(plan) != 0 {
+		t.Fatalf("expected no changes for a matching template, got %v: %v", plan, err)
+	}
+
+	plan, err = client.EnsureSparqlTemplate(ctx, "existing", SparqlTemplate{Id: "http://example.org/template", Query: "CLEAR ALL"})
+	if err != nil || len(plan) != 1 || plan[0].Action != ChangeUpdate {
+		t.Fatalf("expected the template to be updated, got %v: %v", plan, err)
+	}
+
+	plan, err = client.EnsureCustomRole(ctx, "custom_readers", []string{"reader"})
+	if err != nil || len(plan) != 0 {
+		t.Fatalf("expected no changes for a matching custom role, got %v: %v", plan, err)
+	}
+
+	plan, err = client.EnsureCustomRole(ctx, "custom_readers", []string{"reader", "writer"})
+	if err != nil || len(plan) != 1 || plan[0].Action != ChangeUpdate {
+		t.Fatalf("expected the custom role to be updated, got %v: %v", plan, err)
+	}
+
+	plan, err = client.EnsureCustomRole(ctx, "custom_writers", []string{"writer"})
+	if err != nil || len(plan) != 1 || plan[0].Action != ChangeCreate {
+		t.Fatalf("expected the custom role to be created, got %v: %v", plan, err)
+	}
+
+	expected := []string{
+		`POST /rest/repositories {"id":"missing","type":"graphdb"}`,
+		`PUT /rest/repositories/existing/sparql-templates CLEAR ALL`,
+		`PUT /rest/security/custom-roles/custom_readers ["reader","writer"]`,
+		`POST /rest/security/custom-roles/custom_writers ["writer"]`,
+	}
+	if len(applied) != len(expected) {
+		t.Fatalf("unexpected requests: %q", applied)
+	}
+	for i := range expected {
+		if applied[i] != expected[i] {
+			t.Errorf("request %d: expected %q, got %q", i, expected[i], applied[i])
+		}
+	}
+}
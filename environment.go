@@ -0,0 +1,209 @@
+package graphdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// EnvironmentVersion is the version of the environment bundle format written by ExportEnvironment.
+const EnvironmentVersion = 1
+
+// Environment is the portable workbench metadata of a GraphDB server, as written by ExportEnvironment.
+type Environment struct {
+	Version  int         `json:"version"`
+	Created  time.Time   `json:"created"`
+	Source   VersionInfo `json:"source"`
+	Contents State       `json:"contents"`
+}
+
+type environmentOptions struct {
+	passwords       func(username string) string
+	serverPasswords bool
+}
+
+type EnvironmentOption func(opts *environmentOptions)
+
+// WithPasswords provides user passwords, which GraphDB only returns hashed. On export, the passwords are written to the
+// bundle. On import, they fill in the users that have no password in the bundle. Existing users keep their password,
+// users that need to be created without one fail to import.
+func WithPasswords(passwords func(username string) string) EnvironmentOption {
+	return func(opts *environmentOptions) {
+		opts.passwords = passwords
+	}
+}
+
+// WithServerPasswords keeps the location passwords returned by the server in the exported bundle. They are left out by
+// default. The user password hashes are always left out, as importing them would set them as the passwords.
+func WithServerPasswords() EnvironmentOption {
+	return func(opts *environmentOptions) {
+		opts.serverPasswords = true
+	}
+}
+
+// ExportEnvironment writes the configuration of all repositories together with their SPARQL templates, SQL views and
+// ACLs, and the users, custom roles, free access settings, locations and saved queries of the server to w, as a single
+// versioned JSON document. Repository data is not included, use the BackupClient or Export for that. Passwords are left
+// out unless WithPasswords or WithServerPasswords is given.
+func (c *Client) ExportEnvironment(ctx context.Context, w io.Writer, opts ...EnvironmentOption) error {
+	var o environmentOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	version, err := c.info.Version(ctx)
+	if err != nil {
+		return fmt.Errorf("environment: %w", err)
+	}
+
+	state, err := c.currentState(ctx, o)
+	if err != nil {
+		return fmt.Errorf("environment: %w", err)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(Environment{
+		Version:  EnvironmentVersion,
+		Created:  time.Now().UTC(),
+		Source:   version,
+		Contents: state,
+	})
+}
+
+// ImportEnvironment restores a bundle written by ExportEnvironment, creating and updating resources through Reconcile.
+// Resources on the server that are not part of the bundle are left untouched, except custom roles which are replaced.
+func (c *Client) ImportEnvironment(ctx context.Context, r io.Reader, opts ...EnvironmentOption) (Plan, error) {
+	var o environmentOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var env Environment
+	if err := json.NewDecoder(r).Decode(&env); err != nil {
+		return nil, fmt.Errorf("environment: %w", err)
+	}
+
+	if env.Version != EnvironmentVersion {
+		return nil, fmt.Errorf("environment: unsupported bundle version %d", env.Version)
+	}
+
+	state := env.Contents
+	if o.passwords != nil {
+		for i, user := range state.Users {
+			if user.Password == "" {
+				state.Users[i].Password = o.passwords(user.Username)
+			}
+		}
+	}
+	return c.Reconcile(ctx, state)
+}
+
+func (c *Client) currentState(ctx context.Context, o environmentOptions) (State, error) {
+	var state State
+
+	infos, err := c.repository.Infos(ctx)
+	if err != nil {
+		return state, err
+	}
+
+	for _, info := range infos {
+		repo, err := c.repositoryState(ctx, info.Id)
+		if err != nil {
+			return state, fmt.Errorf("repository %s: %w", info.Id, err)
+		}
+		state.Repositories = append(state.Repositories, repo)
+	}
+
+	if state.Users, err = c.security.Users(ctx); err != nil {
+		return state, err
+	}
+
+	for i, user := range state.Users {
+		state.Users[i].DateCreated = 0
+		state.Users[i].GptThreads = nil
+		state.Users[i].Password = ""
+		if o.passwords != nil {
+			state.Users[i].Password = o.passwords(user.Username)
+		}
+	}
+
+	if state.CustomRoles, err = c.security.GetCustomRoles(ctx); err != nil {
+		return state, err
+	}
+
+	if state.CustomRoles == nil {
+		state.CustomRoles = map[string][]string{}
+	}
+
+	free, err := c.security.FreeAccess(ctx)
+	if err != nil {
+		return state, err
+	}
+	state.FreeAccess = &free
+
+	locations, err := c.locations.List(ctx)
+	if err != nil {
+		return state, err
+	}
+
+	// the local location always exists and cannot be added
+	for _, location := range locations {
+		if !location.Local && !location.System {
+			if !o.serverPasswords {
+				location.Password = ""
+			}
+			state.Locations = append(state.Locations, location)
+		}
+	}
+
+	if state.SavedQueries, err = c.savedQueries.SavedQueries(ctx); err != nil {
+		return state, err
+	}
+	return state, nil
+}
+
+func (c *Client) repositoryState(ctx context.Context, id string) (RepositoryState, error) {
+	var repo RepositoryState
+
+	var err error
+	if repo.Config, err = c.repository.Config(ctx, id); err != nil {
+		return repo, err
+	}
+
+	ids, err := c.repository.SparqlTemplates(ctx, id)
+	if err != nil {
+		return repo, err
+	}
+
+	for _, templateId := range ids {
+		template, err := c.repository.SparqlTemplate(ctx, id, templateId)
+		if err != nil {
+			return repo, err
+		}
+		repo.SparqlTemplates = append(repo.SparqlTemplates, template)
+	}
+
+	names, err := c.repository.SqlViews(ctx, id)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return repo, err
+	}
+
+	for _, name := range names {
+		view, err := c.repository.SqlView(ctx, id, name)
+		if err != nil {
+			return repo, err
+		}
+		repo.SqlViews = append(repo.SqlViews, view)
+	}
+
+	acl, err := c.acl.List(ctx, id)
+	if err != nil {
+		return repo, err
+	}
+	repo.Acl = &acl
+	return repo, nil
+}
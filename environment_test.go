@@ -0,0 +1,190 @@
+package graphdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// environmentServer serves the metadata of a server with one repository, a user with a password hash and a location
+// with a password.
+func environmentServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var v any
+		switch r.URL.Path {
+		case PathVersion:
+			v = VersionInfo{Version: "10.8.9"}
+		case PathRepositories:
+			v = []RepositoryInfo{{Id: "repo"}}
+		case "/rest/repositories/repo":
+			v = RepositoryConfig{Id: "repo", Type: "graphdb"}
+		case "/rest/repositories/repo/sparql-templates", PathSqlViews:
+			v = []string{}
+		case "/rest/repositories/repo/acl":
+			_, _ = w.Write([]byte(`[{"scope":"statement","policy":"deny","role":"CUSTOM_X","operation":"read","subject":"*","predicate":"*","object":"*","context":"*"},{"scope":"system","policy":"allow","role":"CUSTOM_Y","operation":"write"}]`))
+			return
+		case PathSecurityUsers:
+			v = []User{{Username: "admin", Password: "{bcrypt}hash", GrantedAuthorities: []string{"ROLE_ADMIN"}, DateCreated: 1}}
+		case PathCustomRoles:
+			v = map[string][]string{"custom_x": {"admin"}}
+		case PathSecurityFreeAccess:
+			v = FreeAccess{}
+		case PathLocations:
+			v = []Location{{Uri: "", Local: true}, {Uri: "http://remote:7200", Password: "remote-secret", LocationType: "rdf4j"}}
+		case PathSavedQueries:
+			v = []SavedQuery{{Name: "all", Body: "SELECT * {?s ?p ?o}"}}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(v)
+	}))
+}
+
+func TestClient_ExportEnvironment(t *testing.T) {
+	server := environmentServer()
+	defer server.Close()
+
+	client := New(server.URL)
+
+	var buf bytes.Buffer
+	err := client.ExportEnvironment(context.Background(), &buf, WithPasswords(func(username string) string {
+		return username + "-secret"
+	}))
+	if err != nil {
+		t.Fatalf("failed to export environment: %v", err)
+	}
+
+	var env Environment
+	if err = json.Unmarshal(buf.Bytes(), &env); err != nil {
+		t.Fatalf("failed to read environment: %v", err)
+	}
+
+	if env.Version != EnvironmentVersion || env.Source.Version != "10.8.9" {
+		t.Errorf("unexpected environment header: %+v", env)
+	}
+
+	state := env.Contents
+	if len(state.Repositories) != 1 || state.Repositories[0].Acl == nil || len(*state.Repositories[0].Acl) != 2 {
+		t.Fatalf("unexpected repositories: %+v", state.Repositories)
+	}
+
+	if policy, ok := (*state.Repositories[0].Acl)[0].(StatementStatement); !ok || policy.Subject != "*" || policy.PolicyType() != "deny" {
+		t.Errorf("expected a statement policy, got %#v", (*state.Repositories[0].Acl)[0])
+	}
+
+	if len(state.Users) != 1 || state.Users[0].Password != "admin-secret" || state.Users[0].DateCreated != 0 {
+		t.Errorf("unexpected users: %+v", state.Users)
+	}
+
+	if len(state.Locations) != 1 || len(state.SavedQueries) != 1 || len(state.CustomRoles) != 1 {
+		t.Errorf("unexpected state: %+v", state)
+	}
+
+	_, err = client.ImportEnvironment(context.Background(), strings.NewReader(`{"version": 2}`))
+	if err == nil {
+		t.Error("expected unsupported version to fail")
+	}
+}
+
+func TestClient_ExportEnvironmentWithoutPasswords(t *testing.T) {
+	server := environmentServer()
+	defer server.Close()
+
+	var buf bytes.Buffer
+	if err := New(server.URL).ExportEnvironment(context.Background(), &buf); err != nil {
+		t.Fatalf("failed to export environment: %v", err)
+	}
+
+	for _, secret := range []string{"{bcrypt}hash", "remote-secret", `"password"`} {
+		if strings.Contains(buf.String(), secret) {
+			t.Errorf("expected no passwords in the bundle, found %q:\n%s", secret, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := New(server.URL).ExportEnvironment(context.Background(), &buf, WithServerPasswords()); err != nil {
+		t.Fatalf("failed to export environment: %v", err)
+	}
+	if strings.Contains(buf.String(), "{bcrypt}hash") || !strings.Contains(buf.String(), "remote-secret") {
+		t.Errorf("expected only the location passwords in the bundle:\n%s", buf.String())
+	}
+}
+
+func TestClient_ImportEnvironment(t *testing.T) {
+	source := environmentServer()
+	defer source.Close()
+
+	for _, serverPasswords := range []bool{false, true} {
+		var opts []EnvironmentOption
+		if serverPasswords {
+			opts = append(opts, WithServerPasswords())
+		}
+
+		var bundle bytes.Buffer
+		if err := New(source.URL).ExportEnvironment(context.Background(), &bundle, opts...); err != nil {
+			t.Fatalf("failed to export environment: %v", err)
+		}
+
+		var applied []string
+		target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				body, _ := io.ReadAll(r.Body)
+				applied = append(applied, r.Method+" "+r.URL.Path+" "+string(body))
+				if r.Method == http.MethodPost && r.URL.Path != PathLocations {
+					w.WriteHeader(http.StatusCreated)
+				}
+				return
+			}
+
+			var v any
+			switch r.URL.Path {
+			case PathRepositories, PathSecurityUsers, PathLocations, PathSavedQueries:
+				v = []any{}
+			case PathCustomRoles:
+				v = map[string][]string{}
+			case PathSecurityFreeAccess:
+				v = FreeAccess{}
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(v)
+		}))
+
+		plan, err := New(target.URL).ImportEnvironment(context.Background(), &bundle, WithPasswords(func(username string) string {
+			return username + "-secret"
+		}))
+		target.Close()
+		if err != nil {
+			t.Fatalf("failed to import environment: %v", err)
+		}
+
+		expected := `create location http://remote:7200
+create repository repo
+update acl repo
+create user admin
+update custom_roles * (custom_x: [] -> [admin])
+create saved_query all
+`
+		if plan.String() != expected {
+			t.Errorf("unexpected plan:\n%s", plan)
+		}
+
+		requests := strings.Join(applied, "\n")
+		if !strings.Contains(requests, "POST /rest/security/users/admin ") || !strings.Contains(requests, `"password":"admin-secret"`) {
+			t.Errorf("expected the user to be created with the provided password:\n%s", requests)
+		}
+		if strings.Contains(requests, "{bcrypt}hash") {
+			t.Errorf("expected no password hashes to be imported:\n%s", requests)
+		}
+		if strings.Contains(requests, "remote-secret") != serverPasswords {
+			t.Errorf("expected the location password to be imported only when exported:\n%s", requests)
+		}
+	}
+}
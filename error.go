@@ -1,9 +1,130 @@
 package graphdb
 
-import "errors"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+)
 
 var (
-	ErrNotFound     = errors.New("not found")
+	ErrBadRequest   = errors.New("bad request")
 	ErrUnauthorized = errors.New("unauthorized")
 	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
 )
+
+// HeaderRequestId is the header read to correlate a failed request with the server logs.
+const HeaderRequestId = "X-Request-Id"
+
+// APIError is returned when GraphDB responds with an unexpected status code. It matches ErrBadRequest,
+// ErrUnauthorized, ErrForbidden, ErrNotFound and ErrConflict with errors.Is, according to the status code.
+type APIError struct {
+	// Op is the operation that failed, e.g. "users" or ClusterGetConfig. It is empty for statuses rejected before
+	// the response reaches the operation.
+	Op         string
+	Method     string
+	Path       string
+	StatusCode int
+	// Message is the error message extracted from the response body.
+	Message string
+	// Body is the parsed response body when it is JSON, nil otherwise.
+	Body      any
+	RequestId string
+}
+
+// NewAPIError reads the response body and creates an APIError for it.
+func NewAPIError(op string, resp *http.Response) *APIError {
+	e := &APIError{Op: op, StatusCode: resp.StatusCode, RequestId: resp.Header.Get(HeaderRequestId)}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.Path = resp.Request.URL.Path
+		if e.RequestId == "" {
+			e.RequestId = resp.Request.Header.Get(HeaderRequestId)
+		}
+	}
+
+	all, _ := io.ReadAll(resp.Body)
+	if json.Unmarshal(all, &e.Body) != nil {
+		e.Body = nil
+	}
+
+	e.Message = errorMessage(e.Body)
+	if e.Message == "" {
+		e.Message = strings.TrimSpace(string(all))
+	}
+	return e
+}
+
+func (e *APIError) Error() string {
+	var sb strings.Builder
+	if e.Op != "" {
+		sb.WriteString(e.Op)
+		sb.WriteString(": ")
+	}
+	if e.Method != "" {
+		fmt.Fprintf(&sb, "%s %s: ", e.Method, e.Path)
+	}
+	fmt.Fprintf(&sb, "%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		sb.WriteString(": ")
+		sb.WriteString(e.Message)
+	}
+	if e.RequestId != "" {
+		fmt.Fprintf(&sb, " (request id %s)", e.RequestId)
+	}
+	return sb.String()
+}
+
+func (e *APIError) Is(target error) bool {
+	switch e.StatusCode {
+	case http.StatusBadRequest:
+		return target == ErrBadRequest
+	case http.StatusUnauthorized:
+		return target == ErrUnauthorized
+	case http.StatusForbidden:
+		return target == ErrForbidden
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusConflict:
+		return target == ErrConflict
+	}
+	return false
+}
+
+// errorMessage extracts a message from the JSON error bodies GraphDB uses: an object with a message or error field,
+// a list of messages, or a map of messages per node as returned by the cluster endpoints.
+func errorMessage(body any) string {
+	switch v := body.(type) {
+	case string:
+		return v
+	case []any:
+		var messages []string
+		for _, m := range v {
+			if s := errorMessage(m); s != "" {
+				messages = append(messages, s)
+			}
+		}
+		return strings.Join(messages, "; ")
+	case map[string]any:
+		for _, key := range []string{"message", "error", "errorMessage"} {
+			if s, ok := v[key].(string); ok && s != "" {
+				return s
+			}
+		}
+
+		var messages []string
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			if s, ok := v[key].(string); ok {
+				messages = append(messages, key+": "+s)
+			}
+		}
+		return strings.Join(messages, "; ")
+	}
+	return ""
+}
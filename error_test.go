@@ -0,0 +1,90 @@
+package graphdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(HeaderRequestId, "abc")
+		switch r.URL.Path {
+		case PathRepositories:
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"message":"Repository test already exists."}`))
+		case PathSecurityUsers:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("invalid user"))
+		case PathSecurity:
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("security not ready"))
+		case PathClusterConfig:
+			w.WriteHeader(http.StatusPreconditionFailed)
+			_, _ = w.Write([]byte(`{"node-1:7300":"not reachable","node-2:7300":"no license"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := New(server.URL)
+
+	err := client.Repositories().Create(ctx, JsonBody(RepositoryConfig{Id: "test"}))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !errors.Is(err, ErrConflict) || errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+
+	expected := `repo: POST /rest/repositories: 409 Conflict: Repository test already exists. (request id abc)`
+	if apiErr.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, apiErr.Error())
+	}
+
+	if _, err = client.Repositories().Infos(ctx); !errors.As(err, &apiErr) || apiErr.Op != "repo" {
+		t.Errorf("expected a repo error, got %v", err)
+	}
+
+	if _, err = client.Security().Enabled(ctx); !errors.As(err, &apiErr) || apiErr.Op != "security" || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected a security error, got %v", err)
+	}
+
+	if _, err = client.Security().Users(ctx); !errors.Is(err, ErrBadRequest) || !errors.As(err, &apiErr) || apiErr.Message != "invalid user" {
+		t.Errorf("expected a bad request with the body as message, got %v", err)
+	}
+
+	if _, err = client.Repositories().Config(ctx, "missing"); !errors.Is(err, ErrNotFound) || !errors.As(err, &apiErr) || apiErr.Op != "repository_get_config" {
+		t.Errorf("expected not found for the operation, got %v", err)
+	}
+
+	_, err = client.Cluster().Config(ctx)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusPreconditionFailed || apiErr.Op != ClusterGetConfig {
+		t.Fatalf("expected a cluster error, got %v", err)
+	}
+
+	if messages := ClusterMessages(apiErr); messages.IsList() || messages.Map()["node-2:7300"] != "no license" {
+		t.Errorf("unexpected cluster messages: %v", messages.Map())
+	}
+
+	if apiErr.Message != "node-1:7300: not reachable; node-2:7300: no license" {
+		t.Errorf("unexpected message: %q", apiErr.Message)
+	}
+}
+
+func TestExpectStatusCode(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusInternalServerError, Header: http.Header{}, Body: http.NoBody, Request: httptest.NewRequest(http.MethodGet, "/", nil)}
+
+	var apiErr *APIError
+	if err := ExpectStatusCode(http.StatusOK)(resp); !errors.As(err, &apiErr) || apiErr.Op != "" {
+		t.Errorf("expected an error without operation, got %v", err)
+	}
+	if err := ExpectOneOfStatusCodeFor("repo", http.StatusOK, http.StatusAccepted)(resp); !errors.As(err, &apiErr) || apiErr.Op != "repo" {
+		t.Errorf("expected a repo error, got %v", err)
+	}
+	if err := ExpectStatusCodeFor("repo", http.StatusInternalServerError)(resp); err != nil {
+		t.Errorf("expected the status to be accepted, got %v", err)
+	}
+}
@@ -0,0 +1,263 @@
+package graphdb
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/yaskoo/go-graphdb/rdf"
+)
+
+const (
+	CompressionNone = ""
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+type ExportOptions struct {
+	// Format of the dump. Defaults to rdf.FormatNQuads.
+	Format rdf.Format
+	// Contexts limits the export to the given graphs. An empty string selects the default graph.
+	Contexts []string
+	// ExplicitOnly skips inferred statements.
+	ExplicitOnly bool
+	// Compression is one of CompressionNone, CompressionGzip or CompressionZstd.
+	Compression string
+	// Checkpoint is the path of a file where exported contexts are recorded. When set, the export is done one context
+	// at a time and a later call with the same options skips the contexts that were already written.
+	// The checkpoint is removed once the export succeeds.
+	Checkpoint string
+	// RequestConfig is applied to every request.
+	RequestConfig []RequestConfig
+}
+
+type ExportStats struct {
+	Contexts int
+	Skipped  int
+	// Bytes is the number of bytes written by this call, output of earlier runs resumed from a checkpoint excluded.
+	Bytes int64
+}
+
+type exportCheckpoint struct {
+	Repository  string     `json:"repository"`
+	Format      rdf.Format `json:"format"`
+	Compression string     `json:"compression"`
+	Offset      int64      `json:"offset"`
+	Completed   []string   `json:"completed"`
+}
+
+// Export streams the statements of a repository to w, optionally compressed.
+//
+// Without a checkpoint the dump is produced by a single request. With a checkpoint every context is exported, and
+// compressed, separately, so the output stays valid at each context boundary. Resuming requires w to continue at the
+// checkpoint offset; an *os.File, or any writer that can Seek and Truncate, is rewound there automatically.
+// Checkpoints are only supported for formats whose documents can be concatenated: N-Quads, N-Triples, TriG and Turtle.
+func (r *RDF4J) Export(ctx context.Context, repo string, w io.Writer, opts ExportOptions) (ExportStats, error) {
+	var stats ExportStats
+	if opts.Format == "" {
+		opts.Format = rdf.FormatNQuads
+	}
+
+	if opts.Compression != CompressionNone && opts.Compression != CompressionGzip && opts.Compression != CompressionZstd {
+		return stats, fmt.Errorf("export: unsupported compression %s", opts.Compression)
+	}
+
+	conf := opts.RequestConfig
+	if opts.ExplicitOnly {
+		conf = append(conf[:len(conf):len(conf)], Query("infer", "false"))
+	}
+
+	cw := &countingWriter{w: w}
+	if opts.Checkpoint == "" {
+		for _, c := range opts.Contexts {
+			conf = append(conf[:len(conf):len(conf)], Query("context", contextParam(c)))
+		}
+
+		err := r.exportCompressed(ctx, repo, cw, opts, conf)
+		stats.Bytes = cw.n
+		stats.Contexts = len(opts.Contexts)
+		return stats, err
+	}
+
+	switch opts.Format {
+	case rdf.FormatNQuads, rdf.FormatNTriples, rdf.FormatTriG, rdf.FormatTurtle:
+	default:
+		return stats, fmt.Errorf("export: checkpoints are not supported for %s", opts.Format)
+	}
+
+	checkpoint := exportCheckpoint{Repository: repo, Format: opts.Format, Compression: opts.Compression}
+	if err := readExportCheckpoint(opts.Checkpoint, &checkpoint); err != nil {
+		return stats, err
+	}
+
+	if err := rewind(w, checkpoint.Offset); err != nil {
+		return stats, err
+	}
+	start := checkpoint.Offset
+	cw.n = start
+
+	contexts := opts.Contexts
+	if len(contexts) == 0 {
+		all, err := r.Contexts(ctx, repo, opts.RequestConfig...)
+		if err != nil {
+			return stats, fmt.Errorf("export: %w", err)
+		}
+		contexts = append([]string{""}, all...)
+	}
+
+	completed := map[string]bool{}
+	for _, c := range checkpoint.Completed {
+		completed[c] = true
+	}
+
+	for _, c := range contexts {
+		if completed[c] {
+			stats.Skipped++
+			continue
+		}
+
+		cc := append(conf[:len(conf):len(conf)], Query("context", contextParam(c)))
+		err := r.exportCompressed(ctx, repo, cw, opts, cc)
+		stats.Bytes = cw.n - start
+		if err != nil {
+			return stats, err
+		}
+		stats.Contexts++
+
+		checkpoint.Completed = append(checkpoint.Completed, c)
+		checkpoint.Offset = cw.n
+		if err := writeExportCheckpoint(opts.Checkpoint, checkpoint); err != nil {
+			return stats, err
+		}
+	}
+
+	if err := os.Remove(opts.Checkpoint); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return stats, fmt.Errorf("export: %w", err)
+	}
+	return stats, nil
+}
+
+func (r *RDF4J) exportCompressed(ctx context.Context, repo string, w io.Writer, opts ExportOptions, conf []RequestConfig) error {
+	var out io.WriteCloser
+	switch opts.Compression {
+	case CompressionGzip:
+		out = gzip.NewWriter(w)
+	case CompressionZstd:
+		enc, err := zstd.NewWriter(w)
+		if err != nil {
+			return fmt.Errorf("export: %w", err)
+		}
+		out = enc
+	default:
+		out = nopWriteCloser{w}
+	}
+
+	err := r.Statements(ctx, repo, opts.Format, func(body io.Reader) error {
+		_, err := io.Copy(out, body)
+		return err
+	}, conf...)
+	if err != nil {
+		_ = out.Close()
+		return fmt.Errorf("export: %w", err)
+	}
+
+	if err = out.Close(); err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+	return nil
+}
+
+// contextParam formats a context for the RDF4J context parameter.
+func contextParam(c string) string {
+	switch {
+	case c == "":
+		return "null"
+	case len(c) > 2 && c[:2] == "_:":
+		return c
+	}
+	return rdf.IRI(c).String()
+}
+
+func readExportCheckpoint(path string, expected *exportCheckpoint) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("export: checkpoint: %w", err)
+	}
+
+	var cp exportCheckpoint
+	if err = json.Unmarshal(data, &cp); err != nil {
+		return fmt.Errorf("export: checkpoint: %w", err)
+	}
+
+	if cp.Repository != expected.Repository || cp.Format != expected.Format || cp.Compression != expected.Compression {
+		return fmt.Errorf("export: checkpoint %s belongs to a different export", path)
+	}
+
+	*expected = cp
+	return nil
+}
+
+func writeExportCheckpoint(path string, cp exportCheckpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("export: checkpoint: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("export: checkpoint: %w", err)
+	}
+
+	if err = os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("export: checkpoint: %w", err)
+	}
+	return nil
+}
+
+// rewind drops anything written after offset by a previous, interrupted export.
+func rewind(w io.Writer, offset int64) error {
+	f, ok := w.(interface {
+		io.Seeker
+		Truncate(size int64) error
+	})
+	if !ok {
+		return nil
+	}
+
+	if err := f.Truncate(offset); err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+	return nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
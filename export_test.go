@@ -0,0 +1,115 @@
+package graphdb
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yaskoo/go-graphdb/rdf"
+)
+
+func TestRDF4J_ExportResume(t *testing.T) {
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repositories/test/contexts":
+			_, _ = w.Write([]byte(`{"results":{"bindings":[{"contextID":{"type":"uri","value":"http://example.org/a"}},{"contextID":{"type":"uri","value":"http://example.org/b"}}]}}`))
+		case "/repositories/test/statements":
+			if r.URL.Query().Get("infer") != "false" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			switch r.URL.Query().Get("context") {
+			case "null":
+				_, _ = w.Write([]byte("<s> <p> <o> .\n"))
+			case "<http://example.org/a>":
+				_, _ = w.Write([]byte("<s> <p> <o> <http://example.org/a> .\n"))
+			case "<http://example.org/b>":
+				if failing {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				_, _ = w.Write([]byte("<s> <p> <o> <http://example.org/b> .\n"))
+			}
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	opts := ExportOptions{
+		ExplicitOnly: true,
+		Compression:  CompressionGzip,
+		Checkpoint:   filepath.Join(dir, "checkpoint.json"),
+	}
+
+	out, err := os.Create(filepath.Join(dir, "dump.nq.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+
+	client := New(server.URL)
+	stats, err := client.RDF4J().Export(context.Background(), "test", out, opts)
+	if err == nil || stats.Contexts != 2 {
+		t.Fatalf("expected export to fail after two contexts, got %+v, %v", stats, err)
+	}
+
+	info, _ := out.Stat()
+	if stats.Bytes == 0 || stats.Bytes != info.Size() {
+		t.Errorf("expected the bytes of the first run, %d, got %d", info.Size(), stats.Bytes)
+	}
+
+	checkpoint := exportCheckpoint{Repository: "test", Format: rdf.FormatNQuads, Compression: CompressionGzip}
+	if err = readExportCheckpoint(opts.Checkpoint, &checkpoint); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = out.Write([]byte("partial garbage"))
+	failing = false
+
+	stats, err = client.RDF4J().Export(context.Background(), "test", out, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.Contexts != 1 || stats.Skipped != 2 {
+		t.Errorf("expected only the last context to be exported, got %+v", stats)
+	}
+
+	info, _ = out.Stat()
+	if stats.Bytes == 0 || stats.Bytes != info.Size()-checkpoint.Offset {
+		t.Errorf("expected only the bytes of the second run, %d, got %d", info.Size()-checkpoint.Offset, stats.Bytes)
+	}
+
+	_, _ = out.Seek(0, io.SeekStart)
+	gz, err := gzip.NewReader(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lines := strings.Count(string(all), "\n"); lines != 3 {
+		t.Errorf("expected 3 statements, got:\n%s", all)
+	}
+
+	if _, err = os.Stat(opts.Checkpoint); !os.IsNotExist(err) {
+		t.Error("expected checkpoint to be removed")
+	}
+
+	// per-context RDF/XML documents cannot be concatenated
+	opts.Format = rdf.FormatRDFXML
+	if _, err = client.RDF4J().Export(context.Background(), "test", io.Discard, opts); err == nil {
+		t.Error("expected checkpoints to be rejected for RDF/XML")
+	}
+}
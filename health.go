@@ -0,0 +1,74 @@
+package graphdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	PathRepositoryHealth = "/repositories/%s/health"
+)
+
+const (
+	HealthCheckReadAvailability     = "read-availability"
+	HealthCheckStorageFolder        = "storage-folder"
+	HealthCheckLongRunningQueries   = "long-running-queries"
+	HealthCheckPredicatesStatistics = "predicates-statistics"
+	HealthCheckPlugins              = "plugins"
+	HealthCheckCluster              = "cluster"
+
+	HealthGreen  = "green"
+	HealthYellow = "yellow"
+	HealthRed    = "red"
+)
+
+type HealthReport struct {
+	Name       string        `json:"name,omitempty"`
+	Status     string        `json:"status,omitempty"`
+	Components []HealthCheck `json:"components,omitempty"`
+}
+
+type HealthCheck struct {
+	Name    string `json:"name,omitempty"`
+	Status  string `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Healthy reports whether the repository is usable, i.e. its status is green or yellow.
+func (h HealthReport) Healthy() bool {
+	return h.Status == HealthGreen || h.Status == HealthYellow
+}
+
+// Check returns the result of a single check, if it was part of the report.
+func (h HealthReport) Check(name string) (HealthCheck, bool) {
+	for _, c := range h.Components {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return HealthCheck{}, false
+}
+
+// Health runs the given health checks against a repository, or all of them if none are given.
+// Unhealthy repositories are not an error: GraphDB answers yellow with a 206 status and red with a 500 status, both with
+// a full report, which is returned as is.
+func (r *RepositoryClient) Health(ctx context.Context, repo string, checks []string, conf ...RequestConfig) (HealthReport, error) {
+	if len(checks) > 0 {
+		conf = append(conf, Query("checks", strings.Join(checks, ",")))
+	}
+
+	var report HealthReport
+	return report, r.client.get(ctx, fmt.Sprintf(PathRepositoryHealth, repo), func(resp *http.Response) error {
+		if err := ErrNotOneOfStatus("health", resp, http.StatusOK, http.StatusPartialContent, http.StatusInternalServerError); err != nil {
+			return err
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+			return fmt.Errorf("health: %w", err)
+		}
+		return nil
+	}, conf...)
+}
@@ -0,0 +1,58 @@
+package graphdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRepository_Health(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/test/health" || r.URL.Query().Get("checks") != "read-availability,storage-folder" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if r.Header.Get("x-status") == "yellow" {
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write([]byte(`{"name":"test","status":"yellow","components":[
+				{"name":"read-availability","status":"green"},
+				{"name":"storage-folder","status":"yellow","message":"low free space"}
+			]}`))
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"name":"test","status":"red","components":[
+			{"name":"read-availability","status":"green"},
+			{"name":"storage-folder","status":"red","message":"not enough free space"}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+	checks := []string{HealthCheckReadAvailability, HealthCheckStorageFolder}
+	report, err := client.Repositories().Health(context.Background(), "test", checks)
+	if err != nil {
+		t.Fatalf("failed to get health report: %v", err)
+	}
+
+	if report.Healthy() {
+		t.Error("expected red repository to be unhealthy")
+	}
+
+	check, ok := report.Check(HealthCheckStorageFolder)
+	if !ok || check.Status != HealthRed || check.Message != "not enough free space" {
+		t.Errorf("unexpected storage folder check: %+v", check)
+	}
+
+	report, err = client.Repositories().Health(context.Background(), "test", checks, Header("x-status", "yellow"))
+	if err != nil {
+		t.Fatalf("failed to get yellow health report: %v", err)
+	}
+
+	if !report.Healthy() || report.Status != HealthYellow {
+		t.Errorf("expected yellow repository to be healthy: %+v", report)
+	}
+}
@@ -0,0 +1,194 @@
+package graphdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	ImportStatusNone         = "NONE"
+	ImportStatusPending      = "PENDING"
+	ImportStatusImporting    = "IMPORTING"
+	ImportStatusInterrupting = "INTERRUPTING"
+	ImportStatusDone         = "DONE"
+	ImportStatusError        = "ERROR"
+)
+
+// importMissingPolls is the number of polls a watched import may be missing from the statuses before giving up on it.
+const importMissingPolls = 5
+
+// importGrace is how long before a wait an import may have finished and still count, covering imports that finish
+// between being started and waited for, and clock differences with the server.
+const importGrace = time.Minute
+
+// ErrImportMissing is reported for a watched import that the server does not know about, or has not started.
+var ErrImportMissing = errors.New("import not found")
+
+// ImportEvent is emitted by WatchImport every time the status of a watched import changes.
+// Polling failures are reported with an empty Name and a non-nil Err, watching continues after them. An import missing
+// from the statuses for several polls is reported with its Name and ErrImportMissing, and is no longer watched.
+type ImportEvent struct {
+	Name              string
+	Status            string
+	Message           string
+	AddedStatements   int
+	RemovedStatements int
+	Err               error
+}
+
+// Terminal reports whether the import has finished, successfully or not.
+func (e ImportEvent) Terminal() bool {
+	return e.Status == ImportStatusDone || e.Status == ImportStatusError
+}
+
+// WatchImport polls the status of the named server file and upload imports, and sends an event each time one changes.
+// The poll interval is configured with WithPolling.
+// The channel is closed once every named import reaches a terminal state or the context is done.
+func (r *RepositoryClient) WatchImport(ctx context.Context, id string, names ...string) <-chan ImportEvent {
+	return r.watchImport(ctx, id, time.Time{}, names)
+}
+
+// watchImport watches the named imports, treating terminal statuses of imports finished before since as missing.
+func (r *RepositoryClient) watchImport(ctx context.Context, id string, since time.Time, names []string) <-chan ImportEvent {
+	events := make(chan ImportEvent)
+
+	go func() {
+		defer close(events)
+
+		send := func(e ImportEvent) bool {
+			select {
+			case events <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		pending := map[string]bool{}
+		for _, name := range names {
+			pending[name] = true
+		}
+		last := map[string]ImportEvent{}
+		missing := map[string]int{}
+
+		p := newPoller(r.client.polling)
+		for len(pending) > 0 {
+			statuses, err := r.importStatuses(ctx, id)
+			if err != nil && ctx.Err() == nil && !send(ImportEvent{Err: err}) {
+				return
+			}
+
+			for _, name := range names {
+				if !pending[name] {
+					continue
+				}
+
+				settings, ok := statuses[name]
+				if !ok || stale(settings, since) {
+					// a failed poll says nothing about the import
+					if err == nil {
+						missing[name]++
+					}
+					if missing[name] >= importMissingPolls {
+						delete(pending, name)
+						if !send(ImportEvent{Name: name, Err: ErrImportMissing}) {
+							return
+						}
+					}
+					continue
+				}
+				missing[name] = 0
+
+				e := ImportEvent{
+					Name:              name,
+					Status:            settings.Status,
+					Message:           settings.Message,
+					AddedStatements:   settings.AddedStatements,
+					RemovedStatements: settings.RemovedStatements,
+				}
+				if prev, seen := last[name]; !seen || prev != e {
+					last[name] = e
+					if !send(e) {
+						return
+					}
+				}
+
+				if e.Terminal() {
+					delete(pending, name)
+				}
+			}
+
+			if len(pending) == 0 {
+				return
+			}
+
+			if p.wait(ctx) != nil {
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// WaitImport blocks until all named imports reach a terminal state, returning their final events in the order of names.
+// Imports that finished well before the wait started are ignored as results of earlier runs.
+// The error joins the messages of failed and missing imports, or is the context error if it expired first.
+func (r *RepositoryClient) WaitImport(ctx context.Context, id string, names ...string) ([]ImportEvent, error) {
+	final := map[string]ImportEvent{}
+	for e := range r.watchImport(ctx, id, time.Now().Add(-importGrace), names) {
+		if e.Name != "" {
+			final[e.Name] = e
+		}
+	}
+
+	var errs []error
+	events := make([]ImportEvent, 0, len(names))
+	for _, name := range names {
+		e, ok := final[name]
+		if !ok {
+			e = ImportEvent{Name: name}
+		}
+		events = append(events, e)
+
+		switch {
+		case e.Err != nil:
+			errs = append(errs, fmt.Errorf("import %s: %w", name, e.Err))
+		case e.Status == ImportStatusError:
+			errs = append(errs, fmt.Errorf("import %s: %s", name, e.Message))
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return events, fmt.Errorf("import: %w", err)
+	}
+	return events, errors.Join(errs...)
+}
+
+// stale reports whether the import finished before since. Imports without a time are never stale.
+func stale(settings ImportSettings, since time.Time) bool {
+	if since.IsZero() || settings.Imported == 0 || (settings.Status != ImportStatusDone && settings.Status != ImportStatusError) {
+		return false
+	}
+	return time.UnixMilli(int64(settings.Imported)).Before(since)
+}
+
+func (r *RepositoryClient) importStatuses(ctx context.Context, id string) (map[string]ImportSettings, error) {
+	statuses := map[string]ImportSettings{}
+
+	server, err := r.ServerFiles(ctx, id)
+	if err != nil {
+		return statuses, err
+	}
+
+	uploads, err := r.UploadFiles(ctx, id)
+	if err != nil {
+		return statuses, err
+	}
+
+	for _, settings := range append(server, uploads...) {
+		statuses[settings.Name] = settings
+	}
+	return statuses, nil
+}
@@ -0,0 +1,94 @@
+package graphdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRepository_WaitImport(t *testing.T) {
+	var polls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/repositories/test/import/upload" {
+			_ = json.NewEncoder(w).Encode([]ImportSettings{{Name: "upload.ttl", Status: ImportStatusError, Message: "parse error"}})
+			return
+		}
+
+		server := []ImportSettings{{Name: "data.ttl", Status: ImportStatusPending}, {Name: "other.ttl", Status: ImportStatusNone}}
+		switch n := polls.Add(1); {
+		case n == 2:
+			server[0].Status = ImportStatusImporting
+		case n >= 3:
+			server[0].Status = ImportStatusDone
+			server[0].AddedStatements = 42
+		}
+		_ = json.NewEncoder(w).Encode(server)
+	}))
+	defer server.Close()
+
+	client := New(server.URL, WithPolling(Polling{Interval: 5 * time.Millisecond}))
+
+	var statuses []string
+	for e := range client.Repositories().WatchImport(context.Background(), "test", "data.ttl") {
+		statuses = append(statuses, e.Status)
+	}
+
+	if len(statuses) != 3 || statuses[0] != ImportStatusPending || statuses[1] != ImportStatusImporting || statuses[2] != ImportStatusDone {
+		t.Errorf("unexpected status transitions: %v", statuses)
+	}
+
+	events, err := client.Repositories().WaitImport(context.Background(), "test", "data.ttl", "upload.ttl")
+	if err == nil || err.Error() != "import upload.ttl: parse error" {
+		t.Errorf("expected failed upload import, got %v", err)
+	}
+
+	if events[0].AddedStatements != 42 || events[1].Status != ImportStatusError {
+		t.Errorf("unexpected final events: %+v", events)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err = client.Repositories().WaitImport(ctx, "test", "other.ttl"); err == nil {
+		t.Error("expected the wait to time out")
+	}
+}
+
+func TestRepository_WaitImportStale(t *testing.T) {
+	var polls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/repositories/test/import/upload" {
+			_ = json.NewEncoder(w).Encode([]ImportSettings{})
+			return
+		}
+
+		// the previous import of data.ttl is reported until the new one starts
+		settings := ImportSettings{Name: "data.ttl", Status: ImportStatusError, Message: "old failure", Imported: int(time.Now().Add(-time.Hour).UnixMilli())}
+		if polls.Add(1) > 2 {
+			settings = ImportSettings{Name: "data.ttl", Status: ImportStatusDone, Imported: int(time.Now().UnixMilli())}
+		}
+		_ = json.NewEncoder(w).Encode([]ImportSettings{settings})
+	}))
+	defer server.Close()
+
+	client := New(server.URL, WithPolling(Polling{Interval: 5 * time.Millisecond}))
+
+	events, err := client.Repositories().WaitImport(context.Background(), "test", "data.ttl")
+	if err != nil || events[0].Status != ImportStatusDone {
+		t.Fatalf("expected the old status to be ignored, got %+v: %v", events, err)
+	}
+
+	start := polls.Load()
+	_, err = client.Repositories().WaitImport(context.Background(), "test", "missing.ttl")
+	if !errors.Is(err, ErrImportMissing) {
+		t.Errorf("expected ErrImportMissing, got %v", err)
+	}
+	if n := polls.Load() - start; n != importMissingPolls {
+		t.Errorf("expected %d polls, got %d", importMissingPolls, n)
+	}
+}
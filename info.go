@@ -3,9 +3,8 @@ package graphdb
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
-
-	"github.com/pkg/errors"
 )
 
 const (
@@ -46,7 +45,9 @@ func (i *InfoClient) Version(ctx context.Context, conf ...RequestConfig) (Versio
 			return err
 		}
 
-		err := json.NewDecoder(resp.Body).Decode(&version)
-		return errors.Wrap(err, "failed to decode version info")
+		if err := json.NewDecoder(resp.Body).Decode(&version); err != nil {
+			return fmt.Errorf("info: %w", err)
+		}
+		return nil
 	}, conf...)
 }
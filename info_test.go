@@ -4,7 +4,7 @@ import (
 	"context"
 	"testing"
 
-	. "go-graphdb/testenv"
+	. "github.com/yaskoo/go-graphdb/testenv"
 )
 
 func TestInfo_Version(t *testing.T) {
@@ -0,0 +1,391 @@
+package graphdb
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yaskoo/go-graphdb/rdf"
+)
+
+type LoadOptions struct {
+	// Format of the file, rdf.FormatNTriples or rdf.FormatNQuads. Detected from the file extension when empty.
+	Format rdf.Format
+	// Context is the named graph N-Triples are loaded into. Ignored for N-Quads.
+	Context string
+	// ChunkBytes is the approximate size of the uncompressed chunks. Defaults to 16MB.
+	ChunkBytes int
+	// Concurrency is the number of chunks uploaded in parallel. Defaults to 4.
+	Concurrency int
+	// Retries is the number of times a failed chunk is retried. Defaults to 3, use a negative value to disable retries.
+	Retries int
+	// RetryDelay is the delay before the first retry, doubled on each subsequent one. Defaults to 1s.
+	RetryDelay time.Duration
+	// Checkpoint is the path of a file where completed chunks are recorded. When set, an interrupted load with the same
+	// file and chunk size resumes from where it stopped. The checkpoint is removed once the load succeeds.
+	Checkpoint string
+	// Progress is called after each chunk is uploaded or fails. Calls are serialized.
+	Progress func(LoadProgress)
+	// RequestConfig is applied to every upload request.
+	RequestConfig []RequestConfig
+}
+
+func (o *LoadOptions) defaults(path string) error {
+	if o.Format == "" {
+		name := strings.TrimSuffix(strings.TrimSuffix(path, ".gz"), ".bz2")
+		switch filepath.Ext(name) {
+		case ".nt":
+			o.Format = rdf.FormatNTriples
+		case ".nq":
+			o.Format = rdf.FormatNQuads
+		default:
+			return fmt.Errorf("loader: cannot detect format of %s", path)
+		}
+	}
+
+	if o.Format != rdf.FormatNTriples && o.Format != rdf.FormatNQuads {
+		return fmt.Errorf("loader: unsupported format %s", o.Format)
+	}
+	if o.ChunkBytes <= 0 {
+		o.ChunkBytes = 16 << 20
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 4
+	}
+	if o.Retries == 0 {
+		o.Retries = 3
+	}
+	if o.RetryDelay <= 0 {
+		o.RetryDelay = time.Second
+	}
+	return nil
+}
+
+// LoadProgress describes the outcome of a single chunk, along with the totals so far.
+type LoadProgress struct {
+	Chunk      int
+	Attempts   int
+	Err        error
+	Chunks     int
+	Failed     int
+	Skipped    int
+	Statements int64
+	Bytes      int64
+}
+
+type LoadStats struct {
+	Chunks     int
+	Failed     int
+	Skipped    int
+	Statements int64
+	Bytes      int64
+}
+
+type loadChunk struct {
+	index int
+	data  []byte
+	lines int64
+}
+
+type loadCheckpoint struct {
+	File       string `json:"file"`
+	Size       int64  `json:"size"`
+	ModTime    int64  `json:"modTime"`
+	ChunkBytes int    `json:"chunkBytes"`
+	Completed  []int  `json:"completed"`
+}
+
+// LoadFile splits a local N-Triples or N-Quads file, optionally gzip or bzip2 compressed, into chunks of whole lines
+// and uploads them concurrently to the repository. Failed chunks are retried, and when a checkpoint is configured,
+// a later call resumes by skipping the chunks that were already uploaded.
+//
+// Blank node labels are scoped to the request they are sent in, so a blank node that appears in more than one chunk
+// is loaded as distinct nodes. Files with shared blank nodes should be skolemized or loaded as a single chunk.
+func (r *RDF4J) LoadFile(ctx context.Context, repo string, path string, opts LoadOptions) (LoadStats, error) {
+	var stats LoadStats
+	if err := opts.defaults(path); err != nil {
+		return stats, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return stats, fmt.Errorf("loader: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return stats, fmt.Errorf("loader: %w", err)
+	}
+
+	checkpoint := loadCheckpoint{File: path, Size: info.Size(), ModTime: info.ModTime().UnixNano(), ChunkBytes: opts.ChunkBytes}
+	completed, err := readCheckpoint(opts.Checkpoint, checkpoint)
+	if err != nil {
+		return stats, err
+	}
+
+	reader, err := decompress(path, file)
+	if err != nil {
+		return stats, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	l := &loader{
+		rdf4j:      r,
+		repo:       repo,
+		opts:       opts,
+		checkpoint: checkpoint,
+		resume:     completed,
+		completed:  map[int]bool{},
+		chunks:     make(chan loadChunk, opts.Concurrency),
+	}
+
+	for i := 0; i < opts.Concurrency; i++ {
+		l.workers.Add(1)
+		go l.upload(ctx)
+	}
+
+	splitErr := l.split(ctx, reader)
+	close(l.chunks)
+	l.workers.Wait()
+
+	stats = l.stats
+	if splitErr != nil {
+		return stats, splitErr
+	}
+
+	if len(l.errs) > 0 {
+		return stats, errors.Join(l.errs...)
+	}
+
+	if opts.Checkpoint != "" {
+		if err = os.Remove(opts.Checkpoint); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return stats, fmt.Errorf("loader: %w", err)
+		}
+	}
+	return stats, nil
+}
+
+type loader struct {
+	rdf4j      *RDF4J
+	repo       string
+	opts       LoadOptions
+	chunks     chan loadChunk
+	workers    sync.WaitGroup
+	mu         sync.Mutex
+	checkpoint loadCheckpoint
+	resume     map[int]bool
+	completed  map[int]bool
+	stats      LoadStats
+	errs       []error
+}
+
+// split reads the input line by line and cuts it into chunks, skipping the ones already completed.
+func (l *loader) split(ctx context.Context, r io.Reader) error {
+	br := bufio.NewReaderSize(r, 1<<20)
+
+	var index int
+	var buf bytes.Buffer
+	var lines int64
+	emit := func() error {
+		defer func() {
+			index++
+			lines = 0
+			buf = bytes.Buffer{}
+		}()
+
+		if l.resume[index] {
+			l.mu.Lock()
+			l.stats.Skipped++
+			l.mu.Unlock()
+			return nil
+		}
+
+		select {
+		case l.chunks <- loadChunk{index: index, data: buf.Bytes(), lines: lines}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			buf.Write(line)
+			if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 && trimmed[0] != '#' {
+				lines++
+			}
+		}
+
+		if buf.Len() >= l.opts.ChunkBytes {
+			if emitErr := emit(); emitErr != nil {
+				return emitErr
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("loader: %w", err)
+		}
+	}
+
+	if buf.Len() > 0 {
+		return emit()
+	}
+	return nil
+}
+
+func (l *loader) upload(ctx context.Context) {
+	defer l.workers.Done()
+
+	for chunk := range l.chunks {
+		attempts, err := l.send(ctx, chunk)
+
+		l.mu.Lock()
+		if err != nil {
+			l.stats.Failed++
+			l.errs = append(l.errs, fmt.Errorf("loader: chunk %d: %w", chunk.index, err))
+		} else {
+			l.stats.Chunks++
+			l.stats.Statements += chunk.lines
+			l.stats.Bytes += int64(len(chunk.data))
+			l.completed[chunk.index] = true
+			if cpErr := l.saveCheckpoint(); cpErr != nil {
+				l.errs = append(l.errs, cpErr)
+			}
+		}
+
+		if l.opts.Progress != nil {
+			l.opts.Progress(LoadProgress{
+				Chunk:      chunk.index,
+				Attempts:   attempts,
+				Err:        err,
+				Chunks:     l.stats.Chunks,
+				Failed:     l.stats.Failed,
+				Skipped:    l.stats.Skipped,
+				Statements: l.stats.Statements,
+				Bytes:      l.stats.Bytes,
+			})
+		}
+		l.mu.Unlock()
+	}
+}
+
+func (l *loader) send(ctx context.Context, chunk loadChunk) (int, error) {
+	conf := l.opts.RequestConfig
+	if l.opts.Format == rdf.FormatNTriples && l.opts.Context != "" {
+		conf = append(conf[:len(conf):len(conf)], Query("context", rdf.IRI(l.opts.Context).String()))
+	}
+
+	delay := l.opts.RetryDelay
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = l.rdf4j.AddStatements(ctx, l.repo, l.opts.Format, bytes.NewReader(chunk.data), conf...)
+		if err == nil || attempt > l.opts.Retries || ctx.Err() != nil {
+			return attempt, err
+		}
+
+		select {
+		case <-time.After(delay):
+			delay *= 2
+		case <-ctx.Done():
+			return attempt, errors.Join(err, ctx.Err())
+		}
+	}
+}
+
+// saveCheckpoint atomically replaces the checkpoint file. Must be called with l.mu held.
+func (l *loader) saveCheckpoint() error {
+	if l.opts.Checkpoint == "" {
+		return nil
+	}
+
+	l.checkpoint.Completed = l.checkpoint.Completed[:0]
+	for index := range l.resume {
+		l.checkpoint.Completed = append(l.checkpoint.Completed, index)
+	}
+	for index := range l.completed {
+		l.checkpoint.Completed = append(l.checkpoint.Completed, index)
+	}
+	sort.Ints(l.checkpoint.Completed)
+
+	data, err := json.Marshal(l.checkpoint)
+	if err != nil {
+		return fmt.Errorf("loader: checkpoint: %w", err)
+	}
+
+	tmp := l.opts.Checkpoint + ".tmp"
+	if err = os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("loader: checkpoint: %w", err)
+	}
+
+	if err = os.Rename(tmp, l.opts.Checkpoint); err != nil {
+		return fmt.Errorf("loader: checkpoint: %w", err)
+	}
+	return nil
+}
+
+// readCheckpoint returns the chunks completed by a previous load of the same file.
+// A checkpoint for a different file, a modified file or a different chunk size is an error rather than being ignored,
+// because resuming it would silently skip or duplicate data.
+func readCheckpoint(path string, expected loadCheckpoint) (map[int]bool, error) {
+	completed := map[int]bool{}
+	if path == "" {
+		return completed, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return completed, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("loader: checkpoint: %w", err)
+	}
+
+	var cp loadCheckpoint
+	if err = json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("loader: checkpoint: %w", err)
+	}
+
+	if cp.File != expected.File || cp.Size != expected.Size || cp.ModTime != expected.ModTime || cp.ChunkBytes != expected.ChunkBytes {
+		return nil, fmt.Errorf("loader: checkpoint %s belongs to a different file or chunk size", path)
+	}
+
+	for _, index := range cp.Completed {
+		completed[index] = true
+	}
+	return completed, nil
+}
+
+func decompress(path string, r io.Reader) (io.Reader, error) {
+	switch filepath.Ext(path) {
+	case ".gz":
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("loader: %w", err)
+		}
+		return gz, nil
+	case ".bz2":
+		return bzip2.NewReader(r), nil
+	}
+	return r, nil
+}
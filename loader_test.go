@@ -0,0 +1,119 @@
+package graphdb
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRDF4J_LoadFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.nt.gz")
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(file)
+	for i := 0; i < 1000; i++ {
+		_, _ = fmt.Fprintf(gz, "<http://example.org/s/%d> <http://example.org/p> \"%d\" .\n", i, i)
+	}
+	_ = gz.Close()
+	_ = file.Close()
+
+	var mu sync.Mutex
+	received := map[string]bool{}
+	attempts := map[string]int{}
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("content-type") != "application/n-triples" || r.URL.Query().Get("context") != "<http://example.org/g>" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var lines []string
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		// the chunk containing the last line fails once with retries and permanently on the first load
+		attempts[lines[0]]++
+		if lines[len(lines)-1] == `<http://example.org/s/999> <http://example.org/p> "999" .` && (failing || attempts[lines[0]] == 1) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		for _, line := range lines {
+			received[line] = true
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+	opts := LoadOptions{
+		Context:     "http://example.org/g",
+		ChunkBytes:  4096,
+		Concurrency: 3,
+		Retries:     -1,
+		RetryDelay:  time.Millisecond,
+		Checkpoint:  filepath.Join(dir, "checkpoint.json"),
+	}
+
+	stats, err := client.RDF4J().LoadFile(context.Background(), "test", path, opts)
+	if err == nil {
+		t.Fatal("expected the first load to fail")
+	}
+
+	if stats.Failed != 1 || stats.Chunks == 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	if _, err = os.Stat(opts.Checkpoint); err != nil {
+		t.Fatalf("expected checkpoint to be written: %v", err)
+	}
+
+	mu.Lock()
+	failing = false
+	attempts = map[string]int{}
+	mu.Unlock()
+
+	var progress []LoadProgress
+	opts.Retries = 2
+	opts.Progress = func(p LoadProgress) {
+		progress = append(progress, p)
+	}
+
+	resumed, err := client.RDF4J().LoadFile(context.Background(), "test", path, opts)
+	if err != nil {
+		t.Fatalf("expected resumed load to succeed: %v", err)
+	}
+
+	if resumed.Chunks != 1 || resumed.Skipped != stats.Chunks {
+		t.Errorf("expected only the failed chunk to be uploaded, got %+v", resumed)
+	}
+
+	if len(progress) != 1 || progress[0].Attempts != 2 {
+		t.Errorf("expected one progress event after a retry, got %+v", progress)
+	}
+
+	if len(received) != 1000 {
+		t.Errorf("expected 1000 statements, got %d", len(received))
+	}
+
+	if _, err = os.Stat(opts.Checkpoint); !os.IsNotExist(err) {
+		t.Error("expected checkpoint to be removed")
+	}
+}
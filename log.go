@@ -0,0 +1,232 @@
+package graphdb
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"maps"
+	"mime"
+	"net/http"
+	"slices"
+	"time"
+)
+
+const redacted = "REDACTED"
+
+// LogOptions configures what the client logs. Headers are logged with authorization headers redacted, and queries are
+// the SPARQL queries and updates sent in request bodies, truncated to MaxQueryLength bytes.
+type LogOptions struct {
+	RequestLevel   slog.Level
+	ResponseLevel  slog.Level
+	ErrorLevel     slog.Level
+	Headers        bool
+	Queries        bool
+	MaxQueryLength int
+}
+
+// WithLogger logs every request, once per attempt, and its response. Unless changed with WithLogOptions, requests and responses are
+// logged at debug level, failures at warn level, and neither headers nor queries are logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(client *Client) {
+		if client.logging == nil {
+			client.logging = &logging{options: LogOptions{RequestLevel: slog.LevelDebug, ResponseLevel: slog.LevelDebug, ErrorLevel: slog.LevelWarn}}
+		}
+		client.logging.logger = logger
+	}
+}
+
+// WithLogOptions replaces the default log options. The zero level is info, and a zero MaxQueryLength is 1024.
+func WithLogOptions(options LogOptions) Option {
+	return func(client *Client) {
+		if client.logging == nil {
+			client.logging = &logging{logger: slog.Default()}
+		}
+		if options.MaxQueryLength <= 0 {
+			options.MaxQueryLength = 1024
+		}
+		client.logging.options = options
+	}
+}
+
+type logging struct {
+	logger  *slog.Logger
+	options LogOptions
+}
+
+// requestLog logs a single request. All methods are no-ops on a nil requestLog.
+type requestLog struct {
+	logging  *logging
+	ctx      context.Context
+	attrs    []slog.Attr
+	start    time.Time
+	status   int
+	received *countingReadCloser
+}
+
+// start returns the request with a requestLog for it in its context. The request itself is logged by logRequest,
+// once the middlewares have run, so it carries their headers.
+func (l *logging) start(req *http.Request, path string) (*http.Request, *requestLog) {
+	if l == nil {
+		return req, nil
+	}
+
+	attrs := []slog.Attr{slog.String("op", operation(req, path)), slog.String("method", req.Method), slog.String("url", req.URL.Redacted())}
+	if repo := repositoryId(path); repo != "" {
+		attrs = append(attrs, slog.String("repository", repo))
+	}
+
+	rl := &requestLog{logging: l, ctx: req.Context(), attrs: attrs, start: time.Now()}
+	return req.WithContext(context.WithValue(req.Context(), requestLogKey{}, rl)), rl
+}
+
+type requestLogKey struct{}
+
+// logRequest logs an attempt to send the request, when it was started with logging.start.
+func logRequest(req *http.Request, attempt int) {
+	rl, _ := req.Context().Value(requestLogKey{}).(*requestLog)
+	if rl == nil {
+		return
+	}
+
+	// the request is logged again for every retry, failover and credentials refresh, its id is the same for all of them
+	l := rl.logging
+	hasId := slices.ContainsFunc(rl.attrs, func(attr slog.Attr) bool { return attr.Key == "request_id" })
+	if id := req.Header.Get(HeaderRequestId); id != "" && !hasId {
+		rl.attrs = append(rl.attrs, slog.String("request_id", id))
+	}
+	if !l.logger.Enabled(rl.ctx, l.options.RequestLevel) {
+		return
+	}
+
+	attrs := append(slices.Clone(rl.attrs), slog.Int("attempt", attempt))
+	if req.ContentLength > 0 {
+		attrs = append(attrs, slog.Int64("bytes_sent", req.ContentLength))
+	}
+	if l.options.Queries {
+		if query := queryText(req, l.options.MaxQueryLength); query != "" {
+			attrs = append(attrs, slog.String("query", query))
+		}
+	}
+	if l.options.Headers {
+		attrs = append(attrs, headersAttr(req.Header))
+	}
+	l.logger.LogAttrs(rl.ctx, l.options.RequestLevel, "graphdb request", attrs...)
+}
+
+// response counts the bytes read from the response body.
+func (rl *requestLog) response(resp *http.Response) {
+	if rl == nil {
+		return
+	}
+
+	rl.status = resp.StatusCode
+	rl.received = &countingReadCloser{ReadCloser: resp.Body}
+	resp.Body = rl.received
+}
+
+func (rl *requestLog) end(err error) {
+	if rl == nil {
+		return
+	}
+
+	level := rl.logging.options.ResponseLevel
+	attrs := append(rl.attrs, slog.Duration("duration", time.Since(rl.start)))
+	if rl.status != 0 {
+		attrs = append(attrs, slog.Int("status", rl.status))
+	}
+	if rl.received != nil {
+		attrs = append(attrs, slog.Int64("bytes_received", rl.received.n))
+	}
+	if err != nil {
+		level = rl.logging.options.ErrorLevel
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	rl.logging.logger.LogAttrs(rl.ctx, level, "graphdb response", attrs...)
+}
+
+// headersAttr returns the headers with the ones carrying credentials redacted.
+func headersAttr(header http.Header) slog.Attr {
+	var attrs []any
+	for _, key := range slices.Sorted(maps.Keys(header)) {
+		value := header.Get(key)
+		switch http.CanonicalHeaderKey(key) {
+		case "Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie":
+			value = redacted
+		}
+		attrs = append(attrs, slog.String(key, value))
+	}
+	return slog.Group("headers", attrs...)
+}
+
+// queryText returns the SPARQL query or update in the request body, truncated to max bytes.
+func queryText(req *http.Request, max int) string {
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("content-type"))
+	if (mediaType != "application/sparql-query" && mediaType != "application/sparql-update") || req.GetBody == nil {
+		return ""
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, int64(max)+1))
+	if err != nil {
+		return ""
+	}
+	if len(data) > max {
+		return string(data[:max]) + "..."
+	}
+	return string(data)
+}
+
+type countingReadCloser struct {
+	io.ReadCloser
+	n int64
+}
+
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// LogValue keeps the password out of logs.
+func (u User) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("username", u.Username),
+		slog.String("password", redactedValue(u.Password)),
+		slog.Any("grantedAuthorities", u.GrantedAuthorities),
+		slog.Any("appSettings", u.AppSettings),
+	)
+}
+
+// LogValue keeps the password out of logs.
+func (u UserDetails) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("username", u.Username),
+		slog.String("password", redactedValue(u.Password)),
+		slog.Any("authorities", u.Authorities),
+		slog.Bool("external", u.External),
+	)
+}
+
+// LogValue keeps the password out of logs.
+func (l Location) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("uri", l.Uri),
+		slog.String("label", l.Label),
+		slog.String("username", l.Username),
+		slog.String("password", redactedValue(l.Password)),
+		slog.String("authType", l.AuthType),
+		slog.String("locationType", l.LocationType),
+	)
+}
+
+func redactedValue(s string) string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
@@ -0,0 +1,160 @@
+package graphdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_Logger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message":"invalid location"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"results":{"bindings":[{"s":{"value":"urn:a"}}]}}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := New(server.URL, WithBasicAuth("admin", "s3cr3t"),
+		WithMiddleware(RequestID(func() string { return "id-1" })),
+		WithLogger(logger),
+		WithLogOptions(LogOptions{RequestLevel: slog.LevelDebug, ResponseLevel: slog.LevelInfo, ErrorLevel: slog.LevelError, Headers: true, Queries: true, MaxQueryLength: 20}),
+	)
+
+	ctx := context.Background()
+	if _, err := client.RDF4J().Select(ctx, "test", "SELECT ?s WHERE { ?s ?p ?o } LIMIT 10", Header("authorization", "Bearer token")); err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if err := client.Locations().Update(ctx, Location{Uri: "http://remote", Username: "remote", Password: "hunter2"}); err == nil {
+		t.Fatal("expected update to fail")
+	}
+
+	output := buf.String()
+	for _, secret := range []string{"s3cr3t", "Bearer token", "hunter2"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("secret %q logged:\n%s", secret, output)
+		}
+	}
+
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		lines = append(lines, entry)
+	}
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 log lines, got %d:\n%s", len(lines), output)
+	}
+
+	request, response := lines[0], lines[1]
+	if request["level"] != "DEBUG" || request["msg"] != "graphdb request" || request["op"] != "rdf4j_query" ||
+		request["repository"] != "test" || request["request_id"] != "id-1" {
+		t.Errorf("unexpected request line: %v", request)
+	}
+	if request["query"] != "SELECT ?s WHERE { ?s..." {
+		t.Errorf("unexpected query: %v", request["query"])
+	}
+	if headers, _ := request["headers"].(map[string]any); headers["Authorization"] != redacted {
+		t.Errorf("authorization header not redacted: %v", request["headers"])
+	}
+
+	if response["level"] != "INFO" || response["status"] != float64(http.StatusOK) || response["request_id"] != "id-1" ||
+		response["bytes_received"] == float64(0) || response["duration"] == nil {
+		t.Errorf("unexpected response line: %v", response)
+	}
+
+	failed := lines[3]
+	if failed["level"] != "ERROR" || failed["status"] != float64(http.StatusBadRequest) ||
+		!strings.Contains(failed["error"].(string), "invalid location") {
+		t.Errorf("unexpected failure line: %v", failed)
+	}
+}
+
+func TestLogValue_Redacted(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Info("values",
+		"user", User{Username: "alice", Password: "p1"},
+		"details", UserDetails{Username: "bob", Password: "p2"},
+		"location", Location{Uri: "http://remote", Password: "p3"},
+	)
+
+	output := buf.String()
+	for _, secret := range []string{"p1", "p2", "p3"} {
+		if strings.Contains(output, `"`+secret+`"`) {
+			t.Errorf("password %q logged: %s", secret, output)
+		}
+	}
+	if !strings.Contains(output, `"username":"alice"`) || strings.Count(output, redacted) != 3 {
+		t.Errorf("unexpected output: %s", output)
+	}
+}
+
+func TestLogRequest_Repeated(t *testing.T) {
+	var buf bytes.Buffer
+	l := &logging{logger: slog.New(slog.NewJSONHandler(&buf, nil)), options: LogOptions{MaxQueryLength: 1024}}
+
+	req := httptest.NewRequest(http.MethodGet, PathRepositories, nil)
+	req.Header.Set(HeaderRequestId, "id-1")
+	req, rl := l.start(req, PathRepositories)
+
+	// a request sent to several nodes is logged once per node
+	for attempt := range 3 {
+		logRequest(req, attempt+1)
+	}
+	if n := strings.Count(buf.String(), `"request_id"`); n != 3 {
+		t.Errorf("expected the request id once per line, got %d:\n%s", n, buf.String())
+	}
+
+	buf.Reset()
+	rl.end(nil)
+	if n := strings.Count(buf.String(), `"request_id"`); n != 1 {
+		t.Errorf("expected the request id once, got %d:\n%s", n, buf.String())
+	}
+}
+
+func TestClient_LoggerRetries(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := New(server.URL, WithLogger(logger), WithRetry(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}))
+	if _, err := client.Repositories().Infos(context.Background()); err != nil {
+		t.Fatalf("expected the retry to succeed: %v", err)
+	}
+
+	var logged []any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		if entry["msg"] == "graphdb request" {
+			logged = append(logged, entry["attempt"])
+		}
+	}
+	if len(logged) != 2 || logged[0] != float64(1) || logged[1] != float64(2) {
+		t.Errorf("expected both attempts to be logged, got %v:\n%s", logged, buf.String())
+	}
+}
@@ -0,0 +1,196 @@
+package graphdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrDryRun is returned for requests skipped by the DryRun middleware.
+var ErrDryRun = errors.New("dry run")
+
+// Doer sends a request and returns its response.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the sending of every request made by the client. Requests reach the middlewares before the
+// credentials are added, and the retries and credential refreshes happen within next.
+type Middleware func(next Doer) Doer
+
+// WithMiddleware adds middlewares to the client. The first middleware added is the outermost one.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(client *Client) {
+		client.middleware = append(client.middleware, middleware...)
+	}
+}
+
+// chain wraps the doer with the middlewares of the client.
+func (c *Client) chain(doer Doer) Doer {
+	for _, m := range slices.Backward(c.middleware) {
+		doer = m(doer)
+	}
+	return doer
+}
+
+// RequestID sets the X-Request-Id header of requests that do not have one, with values from generate, or random UUIDs
+// when it is nil.
+func RequestID(generate func() string) Middleware {
+	if generate == nil {
+		generate = uuid.NewString
+	}
+
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get(HeaderRequestId) == "" {
+				req.Header.Set(HeaderRequestId, generate())
+			}
+			return next.Do(req)
+		})
+	}
+}
+
+// UserAgent sets the User-Agent header of all requests.
+func UserAgent(userAgent string) Middleware {
+	return DefaultHeaders(http.Header{"User-Agent": {userAgent}})
+}
+
+// DefaultHeaders sets the headers on requests that do not have them already.
+func DefaultHeaders(headers http.Header) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			for key, values := range headers {
+				if req.Header.Get(key) == "" {
+					req.Header[http.CanonicalHeaderKey(key)] = slices.Clone(values)
+				}
+			}
+			return next.Do(req)
+		})
+	}
+}
+
+// ReadOnly marks a request that does not change the server regardless of its method, e.g. a SPARQL query sent with
+// POST. Read-only requests are also retried as idempotent.
+func ReadOnly() RequestConfig {
+	return func(req *http.Request) {
+		*req = *req.WithContext(context.WithValue(req.Context(), readOnlyKey{}, true))
+	}
+}
+
+type readOnlyKey struct{}
+
+func readOnly(req *http.Request) bool {
+	v, _ := req.Context().Value(readOnlyKey{}).(bool)
+	return v
+}
+
+// DryRun skips requests that may change the server and fails them with ErrDryRun, after passing them to record when
+// it is not nil. Requests with GET, HEAD and OPTIONS, and requests marked with ReadOnly are sent as usual.
+func DryRun(record func(req *http.Request)) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if !mutating(req) {
+				return next.Do(req)
+			}
+
+			if record != nil {
+				record(req)
+			}
+			if req.Body != nil {
+				_ = req.Body.Close()
+			}
+			return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, ErrDryRun)
+		})
+	}
+}
+
+// AuditEntry describes a request that may have changed the server.
+type AuditEntry struct {
+	Time       time.Time
+	Method     string
+	Path       string
+	Query      string
+	RequestId  string
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+}
+
+// Audit passes an entry for every request that may change the server to record, once the response is received.
+// Requests are classified as in DryRun.
+func Audit(record func(AuditEntry)) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if !mutating(req) {
+				return next.Do(req)
+			}
+
+			entry := AuditEntry{
+				Time:      time.Now(),
+				Method:    req.Method,
+				Path:      req.URL.Path,
+				Query:     req.URL.RawQuery,
+				RequestId: req.Header.Get(HeaderRequestId),
+			}
+
+			resp, err := next.Do(req)
+			entry.Duration = time.Since(entry.Time)
+			entry.Err = err
+			if resp != nil {
+				entry.StatusCode = resp.StatusCode
+			}
+			record(entry)
+			return resp, err
+		})
+	}
+}
+
+// RequestTiming is the time it took to receive the response headers of a request, including retries.
+type RequestTiming struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+}
+
+// Timing passes the timing of every request to record.
+func Timing(record func(RequestTiming)) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.Do(req)
+
+			timing := RequestTiming{Method: req.Method, Path: req.URL.Path, Duration: time.Since(start), Err: err}
+			if resp != nil {
+				timing.StatusCode = resp.StatusCode
+			}
+			record(timing)
+			return resp, err
+		})
+	}
+}
+
+// mutating reports whether the request may change the server.
+func mutating(req *http.Request) bool {
+	if readOnly(req) {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package graphdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestClient_Middleware(t *testing.T) {
+	var mu sync.Mutex
+	var received []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = append(received, r.Header.Clone())
+		mu.Unlock()
+
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.Do(req)
+			})
+		}
+	}
+
+	var entries []AuditEntry
+	var timings []RequestTiming
+	client := New(server.URL, WithBasicAuth("admin", "root"), WithMiddleware(
+		trace("outer"),
+		RequestID(func() string { return "id-1" }),
+		UserAgent("batch-job/1.0"),
+		DefaultHeaders(http.Header{"X-Tenant": {"acme"}}),
+		Audit(func(e AuditEntry) { entries = append(entries, e) }),
+		Timing(func(t RequestTiming) { timings = append(timings, t) }),
+		trace("inner"),
+	))
+
+	ctx := context.Background()
+	if _, err := client.Repositories().Infos(ctx); err != nil {
+		t.Fatalf("failed to list repositories: %v", err)
+	}
+	if err := client.Repositories().Create(ctx, JsonBody(RepositoryConfig{Id: "test"}), Header("x-tenant", "other")); err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+
+	if len(order) != 4 || order[0] != "outer" || order[1] != "inner" {
+		t.Errorf("unexpected middleware order: %v", order)
+	}
+
+	first := received[0]
+	if first.Get(HeaderRequestId) != "id-1" || first.Get("user-agent") != "batch-job/1.0" || first.Get("x-tenant") != "acme" || first.Get("authorization") == "" {
+		t.Errorf("unexpected headers: %v", first)
+	}
+
+	if received[1].Get("x-tenant") != "other" {
+		t.Errorf("expected request headers to take precedence over defaults, got %q", received[1].Get("x-tenant"))
+	}
+
+	if len(entries) != 1 || entries[0].Method != http.MethodPost || entries[0].Path != PathRepositories || entries[0].StatusCode != http.StatusCreated || entries[0].RequestId != "id-1" {
+		t.Errorf("expected only the create to be audited, got %+v", entries)
+	}
+
+	if len(timings) != 2 || timings[0].StatusCode != http.StatusOK || timings[0].Duration <= 0 {
+		t.Errorf("unexpected timings: %+v", timings)
+	}
+}
+
+func TestClient_DryRun(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		_, _ = w.Write([]byte(`{"boolean":true}`))
+	}))
+	defer server.Close()
+
+	var skipped []string
+	client := New(server.URL, WithMiddleware(DryRun(func(req *http.Request) {
+		skipped = append(skipped, req.Method+" "+req.URL.Path)
+	})))
+
+	ctx := context.Background()
+	if ok, err := client.RDF4J().Ask(ctx, "test", "ASK {}"); err != nil || !ok {
+		t.Fatalf("expected read-only query to be sent: %v", err)
+	}
+
+	err := client.Repositories().Delete(ctx, "test")
+	if !errors.Is(err, ErrDryRun) {
+		t.Fatalf("expected the delete to be skipped, got %v", err)
+	}
+
+	if len(skipped) != 1 || skipped[0] != "DELETE /rest/repositories/test" {
+		t.Errorf("unexpected skipped requests: %v", skipped)
+	}
+
+	if len(requests) != 1 || requests[0] != "POST /repositories/test" {
+		t.Errorf("expected only the query to reach the server, got %v", requests)
+	}
+}
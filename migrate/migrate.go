@@ -0,0 +1,358 @@
+// Package migrate applies versioned SPARQL update scripts to a GraphDB repository.
+//
+// Migrations are files named <version>_<name>.ru (or .rq), with an optional <version>_<name>.down.ru that reverts
+// them. Versions are positive integers and are applied in ascending order. Each migration runs in its own RDF4J
+// transaction, together with the record of it, which is kept in a dedicated named graph of the repository.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	graphdb "github.com/yaskoo/go-graphdb"
+	"github.com/yaskoo/go-graphdb/rdf"
+)
+
+const (
+	// DefaultGraph is the named graph where applied migrations are recorded.
+	DefaultGraph = "urn:graphdb:migrations"
+
+	ns          = "urn:graphdb:migration:"
+	xsdDateTime = rdf.IRI("http://www.w3.org/2001/XMLSchema#dateTime")
+	xsdLong     = rdf.IRI("http://www.w3.org/2001/XMLSchema#long")
+	xsdBoolean  = rdf.IRI("http://www.w3.org/2001/XMLSchema#boolean")
+)
+
+var (
+	ErrChecksumMismatch = errors.New("migrate: checksum mismatch")
+	ErrNoDown           = errors.New("migrate: no down migration")
+	ErrUnknownVersion   = errors.New("migrate: unknown version")
+
+	filenamePattern = regexp.MustCompile(`^(\d+)_(.+?)(\.down)?\.(ru|rq)$`)
+)
+
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// Status describes a migration and whether it has been applied. Baseline is set for migrations that were marked as
+// applied by Baseline rather than executed.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	Baseline  bool
+	// Modified is set when the checksum of an applied migration differs from the one of the current file.
+	Modified bool
+}
+
+type Option func(m *Migrator)
+
+// WithGraph changes the named graph where applied migrations are recorded.
+func WithGraph(graph string) Option {
+	return func(m *Migrator) {
+		m.graph = rdf.IRI(graph)
+	}
+}
+
+// WithRequestConfig applies the request configs to every request made by the migrator.
+func WithRequestConfig(conf ...graphdb.RequestConfig) Option {
+	return func(m *Migrator) {
+		m.conf = append(m.conf, conf...)
+	}
+}
+
+// Migrator applies the migrations found in a directory to a repository. It does not guard against concurrent runs.
+type Migrator struct {
+	client     *graphdb.Client
+	repo       string
+	graph      rdf.IRI
+	conf       []graphdb.RequestConfig
+	migrations []Migration
+}
+
+// New loads the migrations from the root of fsys.
+func New(client *graphdb.Client, repo string, fsys fs.FS, opts ...Option) (*Migrator, error) {
+	m := &Migrator{client: client, repo: repo, graph: DefaultGraph}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	migrations, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	m.migrations = migrations
+	return m, nil
+}
+
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Status returns all known migrations in order, with their applied state.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		s := Status{Migration: migration}
+		if a, ok := applied[migration.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = a.AppliedAt
+			s.Baseline = a.Baseline
+			s.Modified = !a.Baseline && a.Checksum != migration.Checksum
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// Up applies all pending migrations in order and returns the ones applied. It refuses to run when an applied
+// migration was modified since, and stops at the first failing migration.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range statuses {
+		if s.Modified {
+			return nil, fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, s.Version, s.Name)
+		}
+	}
+
+	var done []Migration
+	for _, s := range statuses {
+		if s.Applied {
+			continue
+		}
+
+		record := m.record(s.Migration, time.Now(), false)
+		if err = m.transaction(ctx, s.Up, "INSERT DATA { "+record+" }"); err != nil {
+			return done, fmt.Errorf("migrate: %d_%s: %w", s.Version, s.Name, err)
+		}
+		done = append(done, s.Migration)
+	}
+	return done, nil
+}
+
+// Down reverts the most recently applied migration, and returns it. It returns a zero migration when nothing is applied.
+func (m *Migrator) Down(ctx context.Context) (Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return Migration{}, err
+	}
+
+	for i := len(statuses) - 1; i >= 0; i-- {
+		s := statuses[i]
+		if !s.Applied {
+			continue
+		}
+
+		if s.Down == "" && !s.Baseline {
+			return s.Migration, fmt.Errorf("%w: %d_%s", ErrNoDown, s.Version, s.Name)
+		}
+
+		// a baselined migration was never executed by the migrator, so only its record is removed
+		down := s.Down
+		if s.Baseline {
+			down = ""
+		}
+
+		remove := fmt.Sprintf("DELETE WHERE { GRAPH %s { %s ?p ?o } }", m.graph, subject(s.Version))
+		if err = m.transaction(ctx, down, remove); err != nil {
+			return s.Migration, fmt.Errorf("migrate: %d_%s: %w", s.Version, s.Name, err)
+		}
+		return s.Migration, nil
+	}
+	return Migration{}, nil
+}
+
+// Baseline marks all migrations up to and including version as applied, without executing them. It is used to start
+// managing a repository whose schema already matches those migrations.
+func (m *Migrator) Baseline(ctx context.Context, version int64) error {
+	var found bool
+	for _, migration := range m.migrations {
+		found = found || migration.Version == version
+	}
+
+	if !found {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	var records []string
+	now := time.Now()
+	for _, s := range statuses {
+		if s.Version <= version && !s.Applied {
+			records = append(records, m.record(s.Migration, now, true))
+		}
+	}
+
+	if len(records) == 0 {
+		return nil
+	}
+	return m.transaction(ctx, "", "INSERT DATA { "+strings.Join(records, " ")+" }")
+}
+
+// transaction executes the updates, skipping empty ones, in a single transaction, rolling it back on failure.
+func (m *Migrator) transaction(ctx context.Context, updates ...string) error {
+	tx, err := m.client.RDF4J().Begin(ctx, m.repo, m.conf...)
+	if err != nil {
+		return err
+	}
+
+	for _, update := range updates {
+		if strings.TrimSpace(update) == "" {
+			continue
+		}
+
+		if err = tx.Update(ctx, update, m.conf...); err != nil {
+			return errors.Join(err, tx.Rollback(context.WithoutCancel(ctx), m.conf...))
+		}
+	}
+
+	if err = tx.Commit(ctx, m.conf...); err != nil {
+		return errors.Join(err, tx.Rollback(context.WithoutCancel(ctx), m.conf...))
+	}
+	return nil
+}
+
+type appliedRecord struct {
+	Checksum  string
+	AppliedAt time.Time
+	Baseline  bool
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int64]appliedRecord, error) {
+	query := fmt.Sprintf(`SELECT ?version ?checksum ?appliedAt ?baseline WHERE { GRAPH %s {
+	?m <%sversion> ?version ; <%schecksum> ?checksum ; <%sappliedAt> ?appliedAt .
+	OPTIONAL { ?m <%sbaseline> ?baseline }
+} }`, m.graph, ns, ns, ns, ns)
+
+	rows, err := m.client.RDF4J().Select(ctx, m.repo, query, m.conf...)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+
+	applied := map[int64]appliedRecord{}
+	for _, row := range rows {
+		version, err := strconv.ParseInt(row["version"], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: invalid version %q in %s", row["version"], m.graph)
+		}
+
+		at, _ := time.Parse(time.RFC3339Nano, row["appliedAt"])
+		applied[version] = appliedRecord{Checksum: row["checksum"], AppliedAt: at, Baseline: row["baseline"] == "true"}
+	}
+	return applied, nil
+}
+
+// record returns the triples describing an applied migration, inside a GRAPH block.
+func (m *Migrator) record(migration Migration, at time.Time, baseline bool) string {
+	s := subject(migration.Version)
+	statements := []rdf.Statement{
+		{Subject: s, Predicate: rdf.IRI(ns + "version"), Object: rdf.NewTypedLiteral(strconv.FormatInt(migration.Version, 10), xsdLong)},
+		{Subject: s, Predicate: rdf.IRI(ns + "name"), Object: rdf.NewLiteral(migration.Name)},
+		{Subject: s, Predicate: rdf.IRI(ns + "checksum"), Object: rdf.NewLiteral(migration.Checksum)},
+		{Subject: s, Predicate: rdf.IRI(ns + "appliedAt"), Object: rdf.NewTypedLiteral(at.UTC().Format(time.RFC3339Nano), xsdDateTime)},
+	}
+	if baseline {
+		statements = append(statements, rdf.Statement{Subject: s, Predicate: rdf.IRI(ns + "baseline"), Object: rdf.NewTypedLiteral("true", xsdBoolean)})
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "GRAPH %s { ", m.graph)
+	for _, statement := range statements {
+		sb.WriteString(strings.TrimSuffix(statement.String(), "\n"))
+		sb.WriteByte(' ')
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+func subject(version int64) rdf.IRI {
+	return rdf.IRI(ns + strconv.FormatInt(version, 10))
+}
+
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		match := filenamePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migrate: invalid version in %s", entry.Name())
+		}
+
+		data, err := fs.ReadFile(fsys, path.Clean(entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %w", err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migrate: version %d is used by both %s and %s", version, migration.Name, match[2])
+		}
+
+		if match[3] != "" {
+			migration.Down = string(data)
+			continue
+		}
+
+		if migration.Up != "" {
+			return nil, fmt.Errorf("migrate: duplicate migration %s", entry.Name())
+		}
+		sum := sha256.Sum256(data)
+		migration.Up = string(data)
+		migration.Checksum = hex.EncodeToString(sum[:])
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migrate: %d_%s has a down but no up migration", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
@@ -0,0 +1,266 @@
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	graphdb "github.com/yaskoo/go-graphdb"
+)
+
+var (
+	recordPattern   = regexp.MustCompile(`<urn:graphdb:migration:(\d+)> <urn:graphdb:migration:checksum> "([0-9a-f]+)"`)
+	baselinePattern = regexp.MustCompile(`<urn:graphdb:migration:(\d+)> <urn:graphdb:migration:baseline>`)
+	deletePattern   = regexp.MustCompile(`DELETE WHERE \{ GRAPH <urn:graphdb:migrations> \{ <urn:graphdb:migration:(\d+)>`)
+)
+
+type record struct {
+	checksum string
+	baseline bool
+}
+
+// fakeRepository understands just enough of the transaction protocol and the updates sent by the migrator.
+type fakeRepository struct {
+	mu       sync.Mutex
+	records  map[string]record
+	executed []string
+	pending  map[string][]string
+	next     int
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{records: map[string]record{}, pending: map[string][]string{}}
+}
+
+func (f *fakeRepository) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/repositories/test/transactions":
+		f.next++
+		id := string(rune('a' + f.next))
+		f.pending[id] = nil
+		w.Header().Set("location", "http://localhost/repositories/test/transactions/"+id)
+		w.WriteHeader(http.StatusCreated)
+
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/repositories/test/transactions/"):
+		id := strings.TrimPrefix(r.URL.Path, "/repositories/test/transactions/")
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Query().Get("action") {
+		case graphdb.TransactionUpdate:
+			if strings.Contains(string(body), "FAIL") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			f.pending[id] = append(f.pending[id], string(body))
+		case graphdb.TransactionCommit:
+			if slices.ContainsFunc(f.pending[id], func(update string) bool { return strings.Contains(update, "CONFLICT") }) {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			for _, update := range f.pending[id] {
+				f.apply(update)
+			}
+			delete(f.pending, id)
+		}
+		w.WriteHeader(http.StatusOK)
+
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/repositories/test/transactions/"):
+		delete(f.pending, strings.TrimPrefix(r.URL.Path, "/repositories/test/transactions/"))
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == http.MethodPost && r.URL.Path == "/repositories/test":
+		type value struct {
+			Value string `json:"value"`
+		}
+		var bindings []map[string]value
+		for version, rec := range f.records {
+			binding := map[string]value{
+				"version":   {version},
+				"checksum":  {rec.checksum},
+				"appliedAt": {"2024-01-01T00:00:00Z"},
+			}
+			if rec.baseline {
+				binding["baseline"] = value{"true"}
+			}
+			bindings = append(bindings, binding)
+		}
+		w.Header().Set("content-type", "application/sparql-results+json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"results": map[string]any{"bindings": bindings}})
+
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func (f *fakeRepository) apply(update string) {
+	switch {
+	case strings.HasPrefix(update, "INSERT DATA { GRAPH <urn:graphdb:migrations>"):
+		baselined := map[string]bool{}
+		for _, match := range baselinePattern.FindAllStringSubmatch(update, -1) {
+			baselined[match[1]] = true
+		}
+		for _, match := range recordPattern.FindAllStringSubmatch(update, -1) {
+			f.records[match[1]] = record{checksum: match[2], baseline: baselined[match[1]]}
+		}
+	case deletePattern.MatchString(update):
+		delete(f.records, deletePattern.FindStringSubmatch(update)[1])
+	default:
+		f.executed = append(f.executed, update)
+	}
+}
+
+func migrations() fstest.MapFS {
+	return fstest.MapFS{
+		"0001_schema.ru":      {Data: []byte("INSERT DATA { <urn:a> <urn:b> <urn:c> }")},
+		"0001_schema.down.ru": {Data: []byte("DELETE DATA { <urn:a> <urn:b> <urn:c> }")},
+		"0002_labels.rq":      {Data: []byte("INSERT DATA { <urn:a> <urn:label> \"a\" }")},
+		"README.md":           {Data: []byte("ignored")},
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	repo := newFakeRepository()
+	server := httptest.NewServer(repo)
+	defer server.Close()
+
+	ctx := context.Background()
+	m, err := New(graphdb.New(server.URL), "test", migrations())
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+
+	if got := m.Migrations(); len(got) != 2 || got[0].Name != "schema" || got[1].Name != "labels" || got[1].Down != "" {
+		t.Fatalf("unexpected migrations: %+v", got)
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil || len(applied) != 2 {
+		t.Fatalf("expected two migrations to be applied, got %d: %v", len(applied), err)
+	}
+
+	if applied, err = m.Up(ctx); err != nil || len(applied) != 0 {
+		t.Fatalf("expected nothing to be applied, got %d: %v", len(applied), err)
+	}
+
+	if _, err = m.Down(ctx); !errors.Is(err, ErrNoDown) {
+		t.Fatalf("expected no down migration for labels, got %v", err)
+	}
+
+	// drop the labels record to reach the schema migration
+	delete(repo.records, "2")
+	reverted, err := m.Down(ctx)
+	if err != nil || reverted.Version != 1 {
+		t.Fatalf("expected schema to be reverted, got %+v: %v", reverted, err)
+	}
+
+	if len(repo.executed) != 3 || !strings.HasPrefix(repo.executed[2], "DELETE DATA") {
+		t.Errorf("unexpected executed updates: %q", repo.executed)
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("failed to get status: %v", err)
+	}
+
+	for _, s := range statuses {
+		if s.Applied {
+			t.Errorf("expected %d_%s to be pending", s.Version, s.Name)
+		}
+	}
+}
+
+func TestMigrator_Baseline(t *testing.T) {
+	repo := newFakeRepository()
+	server := httptest.NewServer(repo)
+	defer server.Close()
+
+	ctx := context.Background()
+	m, err := New(graphdb.New(server.URL), "test", migrations())
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+
+	if err = m.Baseline(ctx, 3); !errors.Is(err, ErrUnknownVersion) {
+		t.Fatalf("expected unknown version, got %v", err)
+	}
+
+	if err = m.Baseline(ctx, 1); err != nil {
+		t.Fatalf("failed to baseline: %v", err)
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil || len(applied) != 1 || applied[0].Version != 2 {
+		t.Fatalf("expected only labels to be applied, got %+v: %v", applied, err)
+	}
+
+	if len(repo.executed) != 1 {
+		t.Errorf("expected the baselined migration not to be executed: %q", repo.executed)
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil || !statuses[0].Baseline || statuses[1].Baseline {
+		t.Fatalf("unexpected statuses %+v: %v", statuses, err)
+	}
+}
+
+func TestMigrator_Failures(t *testing.T) {
+	repo := newFakeRepository()
+	server := httptest.NewServer(repo)
+	defer server.Close()
+
+	ctx := context.Background()
+	fsys := migrations()
+	fsys["0003_broken.ru"] = &fstest.MapFile{Data: []byte("FAIL")}
+
+	m, err := New(graphdb.New(server.URL), "test", fsys)
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+
+	applied, err := m.Up(ctx)
+	if err == nil || len(applied) != 2 {
+		t.Fatalf("expected the broken migration to fail after two applied, got %d: %v", len(applied), err)
+	}
+
+	if len(repo.pending) != 0 {
+		t.Errorf("expected the failed transaction to be rolled back")
+	}
+
+	// a migration that fails to commit leaves no open transaction
+	fsys["0003_broken.ru"] = &fstest.MapFile{Data: []byte("INSERT DATA { <urn:CONFLICT> <urn:b> <urn:c> }")}
+	if m, err = New(graphdb.New(server.URL), "test", fsys); err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+	if _, err = m.Up(ctx); !errors.Is(err, graphdb.ErrConflict) {
+		t.Fatalf("expected the commit to fail, got %v", err)
+	}
+	if len(repo.pending) != 0 {
+		t.Errorf("expected the uncommitted transaction to be rolled back")
+	}
+
+	// a modified migration blocks further runs
+	fsys["0001_schema.ru"] = &fstest.MapFile{Data: []byte("INSERT DATA { <urn:a> <urn:b> <urn:d> }")}
+	if m, err = New(graphdb.New(server.URL), "test", fsys); err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+
+	if _, err = m.Up(ctx); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+
+	delete(fsys, "0001_schema.ru")
+	if _, err = New(graphdb.New(server.URL), "test", fsys); err == nil {
+		t.Error("expected a down migration without an up migration to fail")
+	}
+}
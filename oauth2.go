@@ -0,0 +1,163 @@
+package graphdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OAuth2Config configures the OAuth2 client credentials grant. Either TokenURL or Issuer must be set, with Issuer the
+// token endpoint is discovered from the OpenID Connect configuration of the issuer.
+type OAuth2Config struct {
+	TokenURL     string
+	Issuer       string
+	ClientID     string
+	ClientSecret Secret
+	Scopes       []string
+	// Audience is sent as the audience parameter, required by some identity providers.
+	Audience string
+	// HTTPClient is used for the token requests, http.DefaultClient when nil.
+	HTTPClient *http.Client
+}
+
+// OAuth2ClientCredentials authenticates with bearer tokens obtained with the client credentials grant. Tokens are
+// cached until shortly before they expire, or until the server rejects them.
+func OAuth2ClientCredentials(config OAuth2Config) CredentialsProvider {
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	return &oauth2Credentials{config: config, tokenURL: config.TokenURL}
+}
+
+type oauth2Credentials struct {
+	config OAuth2Config
+
+	mu       sync.Mutex
+	tokenURL string
+	token    string
+	expiry   time.Time
+}
+
+func (o *oauth2Credentials) Authenticate(req *http.Request) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.token == "" || (!o.expiry.IsZero() && time.Now().Add(tokenRefreshMargin).After(o.expiry)) {
+		if err := o.fetch(req.Context()); err != nil {
+			return err
+		}
+	}
+
+	req.Header.Set("authorization", "Bearer "+o.token)
+	return nil
+}
+
+// Refresh drops the token the server rejected, unless another request has already replaced it.
+func (o *oauth2Credentials) Refresh(req *http.Request) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if req.Header.Get("authorization") == "Bearer "+o.token {
+		o.token = ""
+	}
+	return true
+}
+
+func (o *oauth2Credentials) fetch(ctx context.Context) error {
+	if o.tokenURL == "" {
+		tokenURL, err := o.discover(ctx)
+		if err != nil {
+			return err
+		}
+		o.tokenURL = tokenURL
+	}
+
+	secret, err := o.config.ClientSecret()
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(o.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(o.config.Scopes, " "))
+	}
+	if o.config.Audience != "" {
+		form.Set("audience", o.config.Audience)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("oauth2: %w", err)
+	}
+	req.Header.Set("content-type", "application/x-www-form-urlencoded")
+	req.Header.Set("accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(o.config.ClientID), url.QueryEscape(secret))
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err = o.getJson(req, "oauth2", &token); err != nil {
+		return err
+	}
+
+	if token.AccessToken == "" || (token.TokenType != "" && !strings.EqualFold(token.TokenType, "bearer")) {
+		return fmt.Errorf("oauth2: unsupported token type %q", token.TokenType)
+	}
+
+	o.token = token.AccessToken
+	o.expiry = time.Time{}
+	if token.ExpiresIn > 0 {
+		o.expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return nil
+}
+
+// discover reads the token endpoint from the OpenID Connect configuration of the issuer.
+func (o *oauth2Credentials) discover(ctx context.Context) (string, error) {
+	if o.config.Issuer == "" {
+		return "", errors.New("oauth2: neither token url nor issuer is set")
+	}
+
+	wellKnown := strings.TrimSuffix(o.config.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, wellKnown, nil)
+	if err != nil {
+		return "", fmt.Errorf("oidc_discovery: %w", err)
+	}
+
+	var configuration struct {
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+	if err = o.getJson(req, "oidc_discovery", &configuration); err != nil {
+		return "", err
+	}
+
+	if configuration.TokenEndpoint == "" {
+		return "", errors.New("oidc_discovery: no token endpoint")
+	}
+	return configuration.TokenEndpoint, nil
+}
+
+func (o *oauth2Credentials) getJson(req *http.Request, op string, v any) error {
+	resp, err := o.config.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	if err = ErrNotStatus(http.StatusOK, op, resp); err != nil {
+		return err
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
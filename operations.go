@@ -0,0 +1,151 @@
+package graphdb
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// route maps the method and path of a request to the name of the operation, used in spans, metrics and logs.
+type route struct {
+	method  string
+	pattern *regexp.Regexp
+	op      string
+}
+
+var (
+	routes = []route{
+		newRoute(http.MethodGet, PathRepositories, "repository_list"),
+		newRoute(http.MethodPost, PathRepositories, "repository_create"),
+		newRoute(http.MethodGet, PathRepository, "repository_get_config"),
+		newRoute(http.MethodPut, PathRepository, "repository_edit"),
+		newRoute(http.MethodDelete, PathRepository, "repository_delete"),
+		newRoute(http.MethodGet, PathRepositorySize, "repository_size"),
+		newRoute(http.MethodPost, PathRepositoryRestart, "repository_restart"),
+		newRoute(http.MethodGet, PathRepositoryConfigTurtle, "repository_get_config_turtle"),
+		newRoute(http.MethodGet, PathRepositoryImportServer, "import_server_list"),
+		newRoute(http.MethodPost, PathRepositoryImportServer, "import_server_start"),
+		newRoute(http.MethodDelete, PathRepositoryImportServer, "import_server_cancel"),
+		newRoute(http.MethodGet, PathRepositoryImportUpload, "import_upload_list"),
+		newRoute(http.MethodDelete, PathRepositoryImportUpload, "import_upload_cancel"),
+		newRoute(http.MethodPost, PathRepositoryImportUploadText, "import_upload_text"),
+		newRoute(http.MethodPost, PathRepositoryImportUploadUrl, "import_upload_url"),
+		newRoute(http.MethodPost, PathRepositoryImportUploadFile, "import_upload_file"),
+		newRoute(http.MethodGet, PathRepositorySparqlTemplates, "sparql_template_list"),
+		newRoute(http.MethodPost, PathRepositorySparqlTemplates, "sparql_template_create"),
+		newRoute(http.MethodPut, PathRepositorySparqlTemplates, "sparql_template_update"),
+		newRoute(http.MethodDelete, PathRepositorySparqlTemplates, "sparql_template_delete"),
+		newRoute(http.MethodGet, PathRepositorySparqlTemplatesConf, "sparql_template_get"),
+		newRoute(http.MethodPost, PathRepositorySparqlTemplatesExec, "sparql_template_execute"),
+		newRoute(http.MethodGet, PathSqlViews, "sql_view_list"),
+		newRoute(http.MethodPost, PathSqlViews, "sql_view_create"),
+		newRoute(http.MethodGet, PathSqlViewsTable, "sql_view_get"),
+		newRoute(http.MethodPut, PathSqlViewsTable, "sql_view_update"),
+		newRoute(http.MethodDelete, PathSqlViewsTable, "sql_view_delete"),
+		newRoute(http.MethodGet, PathRepositoryHealth, "repository_health"),
+
+		newRoute(http.MethodGet, PathAccessControlLists, "acl_list"),
+		newRoute(http.MethodPost, PathAccessControlLists, "acl_add"),
+		newRoute(http.MethodPut, PathAccessControlLists, "acl_replace"),
+		newRoute(http.MethodDelete, PathAccessControlLists, "acl_delete"),
+
+		newRoute(http.MethodPost, PathBackup, "backup_create"),
+		newRoute(http.MethodPost, PathBackupCloud, "backup_create_cloud"),
+		newRoute(http.MethodPost, PathBackupRestore, "backup_restore"),
+		newRoute(http.MethodPost, PathBackupRestoreCloud, "backup_restore_cloud"),
+
+		newRoute(http.MethodGet, PathClusterConfig, ClusterGetConfig),
+		newRoute(http.MethodPost, PathClusterConfig, ClusterCreateConfig),
+		newRoute(http.MethodPatch, PathClusterConfig, ClusterUpdateConfig),
+		newRoute(http.MethodDelete, PathClusterConfig, ClusterDeleteConfig),
+		newRoute(http.MethodPost, PathClusterConfigNodes, ClusterAddNodes),
+		newRoute(http.MethodDelete, PathClusterConfigNodes, ClusterDeleteNodes),
+		newRoute(http.MethodPatch, PathClusterConfigNodes, ClusterReplaceNodes),
+		newRoute(http.MethodGet, PathClusterNodeStatus, ClusterNodeStatus),
+		newRoute(http.MethodGet, PathClusterGroupStatus, ClusterGroupStatus),
+		newRoute(http.MethodPost, PathClusterTruncateLog, ClusterTruncateLog),
+		newRoute(http.MethodPost, PathClusterTag, ClusterAddTag),
+		newRoute(http.MethodDelete, PathClusterTag, ClusterRemoveTag),
+		newRoute(http.MethodPost, PathClusterSecondaryMode, ClusterEnableSecondary),
+		newRoute(http.MethodDelete, PathClusterSecondaryMode, ClusterDisableSecondary),
+
+		newRoute(http.MethodPost, PathLogin, "users_login"),
+		newRoute(http.MethodGet, PathSecurity, "security_get"),
+		newRoute(http.MethodPost, PathSecurity, "security_set"),
+		newRoute(http.MethodGet, PathSecurityFreeAccess, "free_access_get"),
+		newRoute(http.MethodPost, PathSecurityFreeAccess, "free_access_set"),
+		newRoute(http.MethodGet, PathSecurityUsers, "users_list"),
+		newRoute(http.MethodGet, PathSecurityUser, "users_get"),
+		newRoute(http.MethodPost, PathSecurityUser, "users_create"),
+		newRoute(http.MethodPut, PathSecurityUser, "users_update"),
+		newRoute(http.MethodPatch, PathSecurityUser, "users_update_settings"),
+		newRoute(http.MethodDelete, PathSecurityUser, "users_delete"),
+		newRoute(http.MethodGet, PathCustomRoles, "custom_roles_get"),
+		newRoute(http.MethodPut, PathCustomRoles, "custom_roles_replace"),
+		newRoute(http.MethodGet, PathCustomRole, "custom_role_users_get"),
+		newRoute(http.MethodPut, PathCustomRole, "custom_role_users_replace"),
+		newRoute(http.MethodPost, PathCustomRole, "custom_role_users_add"),
+		newRoute(http.MethodDelete, PathCustomRole, "custom_role_users_remove"),
+
+		newRoute(http.MethodGet, PathLocations, "location_list"),
+		newRoute(http.MethodPost, PathLocations, "location_add"),
+		newRoute(http.MethodPut, PathLocations, "location_update"),
+		newRoute(http.MethodDelete, PathLocations, "location_delete"),
+		newRoute(http.MethodGet, PathSavedQueries, "saved_query_list"),
+		newRoute(http.MethodPost, PathSavedQueries, "saved_query_create"),
+		newRoute(http.MethodPut, PathSavedQueries, "saved_query_update"),
+
+		newRoute(http.MethodGet, PathMonitorStructs, "monitor_structures"),
+		newRoute(http.MethodGet, PathMonitorInfra, "monitor_infrastructure"),
+		newRoute(http.MethodGet, PathMonitorCluster, "monitor_cluster"),
+		newRoute(http.MethodGet, PathMonitorRecovery, "monitor_backup"),
+		newRoute(http.MethodGet, PathMonitorRepository, "monitor_repository"),
+		newRoute(http.MethodGet, PathReport, "report_download"),
+		newRoute(http.MethodPost, PathReport, "report_generate"),
+		newRoute(http.MethodGet, PathReportStatus, "report_status"),
+		newRoute(http.MethodGet, PathVersion, "info_version"),
+
+		newRoute(http.MethodGet, PathProtocol, "rdf4j_protocol"),
+		newRoute(http.MethodPost, PathQuery, "rdf4j_query"),
+		newRoute(http.MethodGet, PathStatements, "rdf4j_statements_get"),
+		newRoute(http.MethodPost, PathStatements, "rdf4j_statements_add"),
+		newRoute(http.MethodGet, PathContexts, "rdf4j_contexts"),
+		newRoute(http.MethodGet, PathNamespaces, "rdf4j_namespaces"),
+		newRoute(http.MethodPut, PathNamespace, "rdf4j_namespace_set"),
+		newRoute(http.MethodPost, PathTransactions, "rdf4j_transaction_begin"),
+		newRoute(http.MethodPut, PathTransaction, "rdf4j_transaction"),
+		newRoute(http.MethodDelete, PathTransaction, "rdf4j_transaction_rollback"),
+	}
+
+	repositoryPattern = regexp.MustCompile(`/repositor(?:y|ies)/([^/]+)`)
+)
+
+func newRoute(method, path, op string) route {
+	pattern := strings.ReplaceAll(regexp.QuoteMeta(path), "%s", "[^/]+")
+	return route{method: method, pattern: regexp.MustCompile("^" + pattern + "$"), op: op}
+}
+
+// operation returns the name of the operation of a request to path, e.g. cluster_add_node. Transaction actions are
+// named after the action, e.g. rdf4j_transaction_commit.
+func operation(req *http.Request, path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	for _, r := range routes {
+		if r.method != req.Method || !r.pattern.MatchString(path) {
+			continue
+		}
+
+		if action := req.URL.Query().Get("action"); r.op == "rdf4j_transaction" && action != "" {
+			return r.op + "_" + strings.ToLower(action)
+		}
+		return r.op
+	}
+	return "http_" + strings.ToLower(req.Method)
+}
+
+// repositoryId returns the id of the repository a request to path is about, if any.
+func repositoryId(path string) string {
+	if m := repositoryPattern.FindStringSubmatch(path); m != nil {
+		return m[1]
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+package graphdb
+
+import (
+	"context"
+	"time"
+)
+
+// Polling configures how the client polls the server while waiting for long-running operations, such as imports and
+// repository state changes. The delay starts at Interval and is multiplied by Multiplier after each poll, up to
+// MaxInterval.
+type Polling struct {
+	Interval    time.Duration
+	MaxInterval time.Duration
+	Multiplier  float64
+}
+
+func WithPolling(polling Polling) Option {
+	return func(client *Client) {
+		client.polling = polling
+	}
+}
+
+func (p Polling) withDefaults() Polling {
+	if p.Interval <= 0 {
+		p.Interval = time.Second
+	}
+	if p.MaxInterval < p.Interval {
+		p.MaxInterval = 10 * p.Interval
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 1.5
+	}
+	return p
+}
+
+// poller sleeps between polls with an increasing delay.
+type poller struct {
+	polling Polling
+	delay   time.Duration
+}
+
+func newPoller(p Polling) *poller {
+	p = p.withDefaults()
+	return &poller{polling: p, delay: p.Interval}
+}
+
+// wait sleeps for the current delay, returning the context error if it is done first.
+func (p *poller) wait(ctx context.Context) error {
+	timer := time.NewTimer(p.delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+	}
+
+	p.delay = time.Duration(float64(p.delay) * p.polling.Multiplier)
+	if p.delay > p.polling.MaxInterval {
+		p.delay = p.polling.MaxInterval
+	}
+	return nil
+}
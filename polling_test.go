@@ -0,0 +1,33 @@
+package graphdb
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPoller_Backoff(t *testing.T) {
+	p := newPoller(Polling{Interval: time.Millisecond, MaxInterval: 3 * time.Millisecond, Multiplier: 2})
+
+	var delays []time.Duration
+	for i := 0; i < 4; i++ {
+		delays = append(delays, p.delay)
+		if err := p.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []time.Duration{time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond, 3 * time.Millisecond}
+	for i := range expected {
+		if delays[i] != expected[i] {
+			t.Errorf("expected delays %v, got %v", expected, delays)
+			break
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := p.wait(ctx); err == nil {
+		t.Error("expected cancelled context error")
+	}
+}
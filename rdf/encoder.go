@@ -0,0 +1,209 @@
+package rdf
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"unicode/utf16"
+)
+
+// Format is an RDF serialization format, identified by its MIME type.
+type Format string
+
+const (
+	FormatNTriples Format = "application/n-triples"
+	FormatNQuads   Format = "application/n-quads"
+	FormatTurtle   Format = "text/turtle"
+	FormatTriG     Format = "application/trig"
+	FormatRDFXML   Format = "application/rdf+xml"
+	FormatJSONLD   Format = "application/ld+json"
+	FormatBinary   Format = "application/x-binary-rdf"
+)
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	return string(f)
+}
+
+// Extension returns the usual file extension of the format, including the leading dot.
+func (f Format) Extension() string {
+	switch f {
+	case FormatNTriples:
+		return ".nt"
+	case FormatNQuads:
+		return ".nq"
+	case FormatTurtle:
+		return ".ttl"
+	case FormatTriG:
+		return ".trig"
+	case FormatRDFXML:
+		return ".rdf"
+	case FormatJSONLD:
+		return ".jsonld"
+	case FormatBinary:
+		return ".brf"
+	}
+	return ""
+}
+
+// Encoder writes statements in a specific serialization format.
+// Close must be called to flush any buffered data and write the format trailer, it does not close the underlying writer.
+// Flush writes the buffered statements to the underlying writer, without the trailer.
+type Encoder interface {
+	Encode(s Statement) error
+	Flush() error
+	Close() error
+}
+
+// NewEncoder creates an encoder for the given format. Only line based and binary formats can be encoded.
+func NewEncoder(w io.Writer, format Format) (Encoder, error) {
+	switch format {
+	case FormatNQuads, FormatNTriples:
+		return &lineEncoder{w: bufio.NewWriter(w), triples: format == FormatNTriples}, nil
+	case FormatBinary:
+		return newBinaryEncoder(w), nil
+	}
+	return nil, fmt.Errorf("rdf: unsupported encoder format %s", format)
+}
+
+type lineEncoder struct {
+	w       *bufio.Writer
+	triples bool
+}
+
+func (e *lineEncoder) Encode(s Statement) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	if e.triples {
+		s.Context = nil
+	}
+	_, err := e.w.WriteString(s.String())
+	return err
+}
+
+func (e *lineEncoder) Flush() error {
+	return e.w.Flush()
+}
+
+func (e *lineEncoder) Close() error {
+	return e.w.Flush()
+}
+
+// binary RDF format, as defined by RDF4J's BinaryRDFWriter, version 1
+const (
+	binaryFormatVersion = 1
+
+	binaryStatement = 1
+	binaryEndOfData = 127
+
+	binaryNullValue    = 0
+	binaryIRIValue     = 1
+	binaryBNodeValue   = 2
+	binaryPlainLiteral = 3
+	binaryLangLiteral  = 4
+	binaryTypedLiteral = 5
+)
+
+var binaryMagic = []byte("BRDF")
+
+type binaryEncoder struct {
+	w       *bufio.Writer
+	started bool
+}
+
+func newBinaryEncoder(w io.Writer) *binaryEncoder {
+	return &binaryEncoder{w: bufio.NewWriter(w)}
+}
+
+func (e *binaryEncoder) header() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+
+	if _, err := e.w.Write(binaryMagic); err != nil {
+		return err
+	}
+	return binary.Write(e.w, binary.BigEndian, int32(binaryFormatVersion))
+}
+
+func (e *binaryEncoder) Encode(s Statement) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	if err := e.header(); err != nil {
+		return err
+	}
+
+	if err := e.w.WriteByte(binaryStatement); err != nil {
+		return err
+	}
+
+	for _, t := range []Term{s.Subject, s.Predicate, s.Object, s.Context} {
+		if err := e.value(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *binaryEncoder) value(t Term) error {
+	switch v := t.(type) {
+	case nil:
+		return e.w.WriteByte(binaryNullValue)
+	case IRI:
+		return e.tagged(binaryIRIValue, string(v))
+	case BlankNode:
+		return e.tagged(binaryBNodeValue, string(v))
+	case Literal:
+		switch {
+		case v.Lang != "":
+			return e.tagged(binaryLangLiteral, v.Value, v.Lang)
+		case v.Datatype != "" && v.Datatype != XSDString:
+			return e.tagged(binaryTypedLiteral, v.Value, string(v.Datatype))
+		}
+		return e.tagged(binaryPlainLiteral, v.Value)
+	}
+	return fmt.Errorf("rdf: unsupported term %T", t)
+}
+
+func (e *binaryEncoder) tagged(tag byte, values ...string) error {
+	if err := e.w.WriteByte(tag); err != nil {
+		return err
+	}
+
+	for _, v := range values {
+		if err := e.string(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// string writes the value the same way Java's DataOutput.writeChars does, prefixed with the number of UTF-16 units.
+func (e *binaryEncoder) string(s string) error {
+	units := utf16.Encode([]rune(s))
+	if err := binary.Write(e.w, binary.BigEndian, int32(len(units))); err != nil {
+		return err
+	}
+	return binary.Write(e.w, binary.BigEndian, units)
+}
+
+func (e *binaryEncoder) Flush() error {
+	return e.w.Flush()
+}
+
+func (e *binaryEncoder) Close() error {
+	if err := e.header(); err != nil {
+		return err
+	}
+
+	if err := e.w.WriteByte(binaryEndOfData); err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
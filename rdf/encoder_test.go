@@ -0,0 +1,67 @@
+package rdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestNQuadsEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	enc, err := NewEncoder(&buf, FormatNQuads)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statements := []Statement{
+		{Subject: IRI("http://example.org/s"), Predicate: IRI("http://example.org/p"), Object: NewLiteral("a \"quoted\"\nline")},
+		{Subject: BlankNode("b1"), Predicate: IRI("http://example.org/p"), Object: NewLangLiteral("hello", "en"), Context: IRI("http://example.org/g")},
+		{Subject: IRI("http://example.org/s"), Predicate: IRI("http://example.org/p"), Object: NewTypedLiteral("1", "http://www.w3.org/2001/XMLSchema#int")},
+	}
+	for _, s := range statements {
+		if err = enc.Encode(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = enc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<http://example.org/s> <http://example.org/p> "a \"quoted\"\nline" .
+_:b1 <http://example.org/p> "hello"@en <http://example.org/g> .
+<http://example.org/s> <http://example.org/p> "1"^^<http://www.w3.org/2001/XMLSchema#int> .
+`
+	if buf.String() != expected {
+		t.Errorf("unexpected n-quads:\n%s", buf.String())
+	}
+
+	if err = enc.Encode(Statement{Subject: NewLiteral("s"), Predicate: IRI("p"), Object: IRI("o")}); err == nil {
+		t.Error("expected literal subject to be rejected")
+	}
+}
+
+func TestBinaryEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	enc, err := NewEncoder(&buf, FormatBinary)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = enc.Encode(Statement{Subject: IRI("s"), Predicate: IRI("p"), Object: NewLiteral("é")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = enc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var expected bytes.Buffer
+	expected.WriteString("BRDF")
+	_ = binary.Write(&expected, binary.BigEndian, int32(1))
+	expected.Write([]byte{1, 1, 0, 0, 0, 1, 0, 's', 1, 0, 0, 0, 1, 0, 'p', 3, 0, 0, 0, 1, 0, 0xe9, 0, 127})
+
+	if !bytes.Equal(buf.Bytes(), expected.Bytes()) {
+		t.Errorf("unexpected binary rdf:\n%v\n%v", buf.Bytes(), expected.Bytes())
+	}
+}
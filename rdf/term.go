@@ -0,0 +1,157 @@
+// Package rdf contains the minimal RDF model used by the GraphDB client to stream statements to and from a
+// repository, together with encoders for the serialization formats GraphDB accepts.
+package rdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	XSDString     IRI = "http://www.w3.org/2001/XMLSchema#string"
+	RDFLangString IRI = "http://www.w3.org/1999/02/22-rdf-syntax-ns#langString"
+)
+
+// Term is an RDF term, one of IRI, BlankNode or Literal.
+// String returns the N-Triples representation of the term.
+type Term interface {
+	fmt.Stringer
+	term()
+}
+
+type IRI string
+
+func (i IRI) term() {}
+
+func (i IRI) String() string {
+	return "<" + escapeIRI(string(i)) + ">"
+}
+
+type BlankNode string
+
+func (b BlankNode) term() {}
+
+func (b BlankNode) String() string {
+	return "_:" + string(b)
+}
+
+type Literal struct {
+	Value    string
+	Lang     string
+	Datatype IRI
+}
+
+func (l Literal) term() {}
+
+func (l Literal) String() string {
+	s := `"` + escapeLiteral(l.Value) + `"`
+	switch {
+	case l.Lang != "":
+		return s + "@" + l.Lang
+	case l.Datatype != "" && l.Datatype != XSDString:
+		return s + "^^" + l.Datatype.String()
+	}
+	return s
+}
+
+// NewLiteral creates a plain xsd:string literal.
+func NewLiteral(value string) Literal {
+	return Literal{Value: value}
+}
+
+// NewLangLiteral creates a language tagged literal.
+func NewLangLiteral(value, lang string) Literal {
+	return Literal{Value: value, Lang: lang, Datatype: RDFLangString}
+}
+
+// NewTypedLiteral creates a literal with the given datatype.
+func NewTypedLiteral(value string, datatype IRI) Literal {
+	return Literal{Value: value, Datatype: datatype}
+}
+
+// Statement is a single triple or quad. A nil Context means the default graph.
+type Statement struct {
+	Subject   Term
+	Predicate Term
+	Object    Term
+	Context   Term
+}
+
+// Validate checks that the terms are allowed in their position.
+func (s Statement) Validate() error {
+	switch s.Subject.(type) {
+	case IRI, BlankNode:
+	default:
+		return fmt.Errorf("rdf: invalid subject %v", s.Subject)
+	}
+
+	if _, ok := s.Predicate.(IRI); !ok {
+		return fmt.Errorf("rdf: invalid predicate %v", s.Predicate)
+	}
+
+	if s.Object == nil {
+		return fmt.Errorf("rdf: missing object")
+	}
+
+	switch s.Context.(type) {
+	case nil, IRI, BlankNode:
+	default:
+		return fmt.Errorf("rdf: invalid context %v", s.Context)
+	}
+	return nil
+}
+
+// String returns the statement as a single N-Quads line, including the trailing newline.
+func (s Statement) String() string {
+	var sb strings.Builder
+	sb.WriteString(s.Subject.String())
+	sb.WriteByte(' ')
+	sb.WriteString(s.Predicate.String())
+	sb.WriteByte(' ')
+	sb.WriteString(s.Object.String())
+	if s.Context != nil {
+		sb.WriteByte(' ')
+		sb.WriteString(s.Context.String())
+	}
+	sb.WriteString(" .\n")
+	return sb.String()
+}
+
+func escapeIRI(s string) string {
+	if !strings.ContainsAny(s, "<>\"{}|^`\\ \t\r\n") {
+		return s
+	}
+
+	var sb strings.Builder
+	for _, r := range s {
+		if r <= 0x20 || strings.ContainsRune("<>\"{}|^`\\", r) {
+			fmt.Fprintf(&sb, "\\u%04X", r)
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func escapeLiteral(s string) string {
+	if !strings.ContainsAny(s, "\"\\\n\r") {
+		return s
+	}
+
+	var sb strings.Builder
+	for _, r := range s {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
@@ -2,17 +2,36 @@ package graphdb
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/yaskoo/go-graphdb/rdf"
 )
 
 const (
 	PathProtocol     = "/protocol"
+	PathQuery        = "/repositories/%s"
+	PathStatements   = "/repositories/%s/statements"
+	PathContexts     = "/repositories/%s/contexts"
+	PathNamespaces   = "/repositories/%s/namespaces"
+	PathNamespace    = "/repositories/%s/namespaces/%s"
 	PathTransactions = "/repositories/%s/transactions"
 	PathTransaction  = "/repositories/%s/transactions/%s"
 )
 
+const (
+	TransactionAdd      = "ADD"
+	TransactionDelete   = "DELETE"
+	TransactionUpdate   = "UPDATE"
+	TransactionCommit   = "COMMIT"
+	TransactionRollback = "ROLLBACK"
+)
+
 type RDF4J struct {
 	client *Client
 }
@@ -20,8 +39,8 @@ type RDF4J struct {
 func (r *RDF4J) Protocol(ctx context.Context, config ...RequestConfig) (string, error) {
 	var v string
 	return v, r.client.get(ctx, PathProtocol, func(resp *http.Response) error {
-		if resp.StatusCode != http.StatusOK {
-			return ErrNotStatus(http.StatusOK, "rdf4j", resp)
+		if err := ErrNotStatus(http.StatusOK, "rdf4j", resp); err != nil {
+			return err
 		}
 
 		all, err := io.ReadAll(resp.Body)
@@ -32,3 +51,209 @@ func (r *RDF4J) Protocol(ctx context.Context, config ...RequestConfig) (string,
 		return nil
 	}, config...)
 }
+
+// Ask evaluates a SPARQL ASK query against the repository.
+func (r *RDF4J) Ask(ctx context.Context, repo string, query string, config ...RequestConfig) (bool, error) {
+	var result struct {
+		Boolean bool `json:"boolean"`
+	}
+	config = append([]RequestConfig{ReadOnly()}, config...)
+	config = append(config, Header("content-type", "application/sparql-query"), Header("accept", "application/sparql-results+json"))
+	return result.Boolean, r.client.post(ctx, fmt.Sprintf(PathQuery, repo), strings.NewReader(query), func(resp *http.Response) error {
+		if err := ErrNotStatus(http.StatusOK, "rdf4j_query", resp); err != nil {
+			return err
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return fmt.Errorf("rdf4j_query: %w", err)
+		}
+		return nil
+	}, config...)
+}
+
+// Select evaluates a SPARQL SELECT query against the repository and returns the value of each binding, per result.
+func (r *RDF4J) Select(ctx context.Context, repo string, query string, config ...RequestConfig) ([]map[string]string, error) {
+	var rows []map[string]string
+	config = append([]RequestConfig{ReadOnly()}, config...)
+	config = append(config, Header("content-type", "application/sparql-query"), Header("accept", "application/sparql-results+json"))
+	return rows, r.client.post(ctx, fmt.Sprintf(PathQuery, repo), strings.NewReader(query), func(resp *http.Response) error {
+		if err := ErrNotStatus(http.StatusOK, "rdf4j_query", resp); err != nil {
+			return err
+		}
+
+		var results struct {
+			Results struct {
+				Bindings []map[string]struct {
+					Value string `json:"value"`
+				} `json:"bindings"`
+			} `json:"results"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+			return fmt.Errorf("rdf4j_query: %w", err)
+		}
+
+		for _, binding := range results.Results.Bindings {
+			row := make(map[string]string, len(binding))
+			for name, v := range binding {
+				row[name] = v.Value
+			}
+			rows = append(rows, row)
+		}
+		recordResults(resp, len(rows))
+		return nil
+	}, config...)
+}
+
+// AddStatements uploads serialized statements in the given format to the repository.
+// Use Query("context", "<iri>") to load triples into a named graph.
+func (r *RDF4J) AddStatements(ctx context.Context, repo string, format rdf.Format, body io.Reader, config ...RequestConfig) error {
+	config = append(config, Header("content-type", format.ContentType()))
+	return r.client.post(ctx, fmt.Sprintf(PathStatements, repo), body, func(resp *http.Response) error {
+		return ErrNotStatus(http.StatusNoContent, "rdf4j_statements", resp)
+	}, config...)
+}
+
+// Statements streams the statements of the repository in the requested format to the consumer.
+// Use Query("context", "<iri>") to limit the export to specific graphs, and Query("infer", "false") to skip inferred statements.
+func (r *RDF4J) Statements(ctx context.Context, repo string, format rdf.Format, consumer func(r io.Reader) error, config ...RequestConfig) error {
+	config = append(config, Header("accept", format.ContentType()))
+	return r.client.get(ctx, fmt.Sprintf(PathStatements, repo), func(resp *http.Response) error {
+		if err := ErrNotStatus(http.StatusOK, "rdf4j_statements", resp); err != nil {
+			return err
+		}
+		return consumer(resp.Body)
+	}, config...)
+}
+
+// Contexts lists the named graphs of the repository, IRIs as is and blank nodes in their "_:id" form.
+func (r *RDF4J) Contexts(ctx context.Context, repo string, config ...RequestConfig) ([]string, error) {
+	var contexts []string
+	config = append(config, Header("accept", "application/sparql-results+json"))
+	return contexts, r.client.get(ctx, fmt.Sprintf(PathContexts, repo), func(resp *http.Response) error {
+		if err := ErrNotStatus(http.StatusOK, "rdf4j_contexts", resp); err != nil {
+			return err
+		}
+
+		var results struct {
+			Results struct {
+				Bindings []map[string]struct {
+					Type  string `json:"type"`
+					Value string `json:"value"`
+				} `json:"bindings"`
+			} `json:"results"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+			return fmt.Errorf("rdf4j_contexts: %w", err)
+		}
+
+		for _, binding := range results.Results.Bindings {
+			v := binding["contextID"]
+			if v.Type == "bnode" {
+				contexts = append(contexts, "_:"+v.Value)
+				continue
+			}
+			contexts = append(contexts, v.Value)
+		}
+		return nil
+	}, config...)
+}
+
+// Namespaces returns the namespace declarations of the repository, keyed by prefix.
+func (r *RDF4J) Namespaces(ctx context.Context, repo string, config ...RequestConfig) (map[string]string, error) {
+	namespaces := map[string]string{}
+	config = append(config, Header("accept", "application/sparql-results+json"))
+	return namespaces, r.client.get(ctx, fmt.Sprintf(PathNamespaces, repo), func(resp *http.Response) error {
+		if err := ErrNotStatus(http.StatusOK, "rdf4j_namespaces", resp); err != nil {
+			return err
+		}
+
+		var results struct {
+			Results struct {
+				Bindings []map[string]struct {
+					Value string `json:"value"`
+				} `json:"bindings"`
+			} `json:"results"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+			return fmt.Errorf("rdf4j_namespaces: %w", err)
+		}
+
+		for _, binding := range results.Results.Bindings {
+			namespaces[binding["prefix"].Value] = binding["namespace"].Value
+		}
+		return nil
+	}, config...)
+}
+
+// SetNamespace creates or replaces the namespace declaration for prefix.
+func (r *RDF4J) SetNamespace(ctx context.Context, repo, prefix, namespace string, config ...RequestConfig) error {
+	config = append(config, Header("content-type", "text/plain"))
+	return r.client.put(ctx, fmt.Sprintf(PathNamespace, repo, url.PathEscape(prefix)), strings.NewReader(namespace), func(resp *http.Response) error {
+		return ErrNotStatus(http.StatusNoContent, "rdf4j_namespaces", resp)
+	}, config...)
+}
+
+// Begin starts a new RDF4J transaction in the repository.
+func (r *RDF4J) Begin(ctx context.Context, repo string, config ...RequestConfig) (*Transaction, error) {
+	tx := &Transaction{client: r.client, repo: repo}
+	return tx, r.client.post(ctx, fmt.Sprintf(PathTransactions, repo), nil, func(resp *http.Response) error {
+		if err := ErrNotStatus(http.StatusCreated, "rdf4j_transaction", resp); err != nil {
+			return err
+		}
+
+		location, err := url.Parse(resp.Header.Get("location"))
+		if err != nil || location.Path == "" {
+			return fmt.Errorf("rdf4j_transaction: invalid location %q", resp.Header.Get("location"))
+		}
+		tx.id = path.Base(location.Path)
+		return nil
+	}, config...)
+}
+
+// Transaction is an open RDF4J transaction. It is not safe for concurrent use.
+type Transaction struct {
+	client *Client
+	repo   string
+	id     string
+}
+
+func (t *Transaction) Id() string {
+	return t.id
+}
+
+// Add adds serialized statements in the given format to the transaction.
+func (t *Transaction) Add(ctx context.Context, format rdf.Format, body io.Reader, config ...RequestConfig) error {
+	config = append(config, Header("content-type", format.ContentType()))
+	return t.action(ctx, TransactionAdd, body, config...)
+}
+
+// Remove deletes serialized statements in the given format as part of the transaction.
+func (t *Transaction) Remove(ctx context.Context, format rdf.Format, body io.Reader, config ...RequestConfig) error {
+	config = append(config, Header("content-type", format.ContentType()))
+	return t.action(ctx, TransactionDelete, body, config...)
+}
+
+// Update executes a SPARQL update as part of the transaction.
+func (t *Transaction) Update(ctx context.Context, update string, config ...RequestConfig) error {
+	config = append(config, Header("content-type", "application/sparql-update"))
+	return t.action(ctx, TransactionUpdate, strings.NewReader(update), config...)
+}
+
+func (t *Transaction) Commit(ctx context.Context, config ...RequestConfig) error {
+	return t.action(ctx, TransactionCommit, nil, config...)
+}
+
+func (t *Transaction) Rollback(ctx context.Context, config ...RequestConfig) error {
+	return t.client.delete(ctx, fmt.Sprintf(PathTransaction, t.repo, t.id), nil, func(resp *http.Response) error {
+		return ErrNotStatus(http.StatusNoContent, "rdf4j_transaction", resp)
+	}, config...)
+}
+
+func (t *Transaction) action(ctx context.Context, action string, body io.Reader, config ...RequestConfig) error {
+	// transaction actions change the state of the transaction, a retried ADD would add the data twice
+	config = append([]RequestConfig{NonIdempotent()}, config...)
+	config = append(config, Query("action", action))
+	return t.client.put(ctx, fmt.Sprintf(PathTransaction, t.repo, t.id), body, func(resp *http.Response) error {
+		return ErrNotOneOfStatus("rdf4j_transaction", resp, http.StatusOK, http.StatusNoContent)
+	}, config...)
+}
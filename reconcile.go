@@ -0,0 +1,554 @@
+package graphdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ChangeCreate = "create"
+	ChangeUpdate = "update"
+
+	ResourceRepository     = "repository"
+	ResourceSparqlTemplate = "sparql_template"
+	ResourceSqlView        = "sql_view"
+	ResourceAcl            = "acl"
+	ResourceUser           = "user"
+	ResourceCustomRoles    = "custom_roles"
+	ResourceFreeAccess     = "free_access"
+	ResourceLocation       = "location"
+	ResourceSavedQuery     = "saved_query"
+)
+
+// State is the desired state of a GraphDB server. Only the resources listed are managed, anything else found on the
+// server is left untouched. CustomRoles is the exception, when set it replaces all custom roles.
+type State struct {
+	Repositories []RepositoryState   `json:"repositories,omitempty"`
+	Users        []User              `json:"users,omitempty"`
+	CustomRoles  map[string][]string `json:"customRoles,omitempty"`
+	FreeAccess   *FreeAccess         `json:"freeAccess,omitempty"`
+	Locations    []Location          `json:"locations,omitempty"`
+	SavedQueries []SavedQuery        `json:"savedQueries,omitempty"`
+}
+
+// RepositoryState is a repository with the SPARQL templates, SQL views and ACL it should have. Only the config params
+// that are set are compared with the server, the remaining ones keep their current values. A nil Acl leaves the ACL
+// of the repository untouched, an empty one clears it.
+type RepositoryState struct {
+	Config          RepositoryConfig `json:"config"`
+	SparqlTemplates []SparqlTemplate `json:"sparqlTemplates,omitempty"`
+	SqlViews        []SqlView        `json:"sqlViews,omitempty"`
+	Acl             *Policies        `json:"acl,omitempty"`
+}
+
+// LoadState reads a desired state in YAML or JSON. Field names are the same as in the JSON form of the types.
+func LoadState(r io.Reader) (State, error) {
+	var state State
+
+	var doc any
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return state, fmt.Errorf("state: %w", err)
+	}
+
+	// yaml is converted to json, so the json tags and custom unmarshalers of the client types apply
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return state, fmt.Errorf("state: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&state); err != nil {
+		return state, fmt.Errorf("state: %w", err)
+	}
+	return state, nil
+}
+
+// Change is a single create or update needed to bring the server to the desired state.
+type Change struct {
+	Action   string
+	Resource string
+	Name     string
+	Diff     []string
+
+	apply func(ctx context.Context) error
+}
+
+func (c Change) String() string {
+	s := fmt.Sprintf("%s %s %s", c.Action, c.Resource, c.Name)
+	if len(c.Diff) > 0 {
+		s += " (" + strings.Join(c.Diff, ", ") + ")"
+	}
+	return s
+}
+
+type Plan []Change
+
+// String renders the plan one change per line.
+func (p Plan) String() string {
+	if len(p) == 0 {
+		return "no changes\n"
+	}
+
+	var sb strings.Builder
+	for _, c := range p {
+		sb.WriteString(c.String())
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// Plan compares the desired state with the server and returns the changes needed, without applying them.
+func (c *Client) Plan(ctx context.Context, desired State) (Plan, error) {
+	var plan Plan
+	steps := []func(context.Context, State) (Plan, error){
+		c.planLocations,
+		c.planRepositories,
+		c.planUsers,
+		c.planCustomRoles,
+		c.planFreeAccess,
+		c.planSavedQueries,
+	}
+
+	for _, step := range steps {
+		changes, err := step(ctx, desired)
+		if err != nil {
+			return plan, fmt.Errorf("plan: %w", err)
+		}
+		plan = append(plan, changes...)
+	}
+	return plan, nil
+}
+
+// Reconcile plans and applies the changes needed to bring the server to the desired state. Changes are applied in
+// order and it stops at the first failure. The returned plan contains all planned changes.
+func (c *Client) Reconcile(ctx context.Context, desired State) (Plan, error) {
+	plan, err := c.Plan(ctx, desired)
+	if err != nil {
+		return plan, err
+	}
+
+	for _, change := range plan {
+		if err = change.apply(ctx); err != nil {
+			return plan, fmt.Errorf("reconcile: %s: %w", change, err)
+		}
+	}
+	return plan, nil
+}
+
+func (c *Client) planLocations(ctx context.Context, desired State) (Plan, error) {
+	if len(desired.Locations) == 0 {
+		return nil, nil
+	}
+
+	current, err := c.locations.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[string]Location{}
+	for _, l := range current {
+		existing[l.Uri] = l
+	}
+
+	var plan Plan
+	for _, location := range desired.Locations {
+		cur, ok := existing[location.Uri]
+		if !ok {
+			plan = append(plan, Change{Action: ChangeCreate, Resource: ResourceLocation, Name: location.Uri, apply: func(ctx context.Context) error {
+				return c.locations.Add(ctx, location)
+			}})
+			continue
+		}
+
+		// passwords are never returned, so they cannot be compared
+		var diff []string
+		diff = diffField(diff, "label", cur.Label, location.Label)
+		diff = diffField(diff, "username", cur.Username, location.Username)
+		diff = diffField(diff, "authType", cur.AuthType, location.AuthType)
+		diff = diffField(diff, "locationType", cur.LocationType, location.LocationType)
+		diff = diffField(diff, "defaultRepository", cur.DefaultRepository, location.DefaultRepository)
+		if len(diff) > 0 {
+			plan = append(plan, Change{Action: ChangeUpdate, Resource: ResourceLocation, Name: location.Uri, Diff: diff, apply: func(ctx context.Context) error {
+				return c.locations.Update(ctx, location)
+			}})
+		}
+	}
+	return plan, nil
+}
+
+func (c *Client) planRepositories(ctx context.Context, desired State) (Plan, error) {
+	if len(desired.Repositories) == 0 {
+		return nil, nil
+	}
+
+	infos, err := c.repository.Infos(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[string]bool{}
+	for _, info := range infos {
+		existing[info.Id] = true
+	}
+
+	var plan Plan
+	for _, repo := range desired.Repositories {
+		id := repo.Config.Id
+		if !existing[id] {
+			plan = append(plan, Change{Action: ChangeCreate, Resource: ResourceRepository, Name: id, apply: func(ctx context.Context) error {
+				return c.repository.Create(ctx, JsonBody(repo.Config))
+			}})
+
+			for _, template := range repo.SparqlTemplates {
+				plan = append(plan, c.createSparqlTemplate(id, template))
+			}
+			for _, view := range repo.SqlViews {
+				plan = append(plan, c.createSqlView(id, view))
+			}
+			if repo.Acl != nil && len(*repo.Acl) > 0 {
+				plan = append(plan, c.replaceAcl(id, *repo.Acl, nil))
+			}
+			continue
+		}
+
+		current, err := c.repository.Config(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		var diff []string
+		diff = diffField(diff, "title", current.Title, repo.Config.Title)
+		for _, name := range slices.Sorted(maps.Keys(repo.Config.Params)) {
+			diff = diffField(diff, name, current.Params[name].Value.String(), repo.Config.Params[name].Value.String())
+		}
+
+		if len(diff) > 0 {
+			merged := current
+			merged.Params = maps.Clone(current.Params)
+			if repo.Config.Title != "" {
+				merged.Title = repo.Config.Title
+			}
+			maps.Copy(merged.Params, repo.Config.Params)
+
+			plan = append(plan, Change{Action: ChangeUpdate, Resource: ResourceRepository, Name: id, Diff: diff, apply: func(ctx context.Context) error {
+				return c.repository.Edit(ctx, id, merged)
+			}})
+		}
+
+		templates, err := c.planSparqlTemplates(ctx, id, repo.SparqlTemplates)
+		if err != nil {
+			return nil, err
+		}
+		plan = append(plan, templates...)
+
+		views, err := c.planSqlViews(ctx, id, repo.SqlViews)
+		if err != nil {
+			return nil, err
+		}
+		plan = append(plan, views...)
+
+		acl, err := c.planAcl(ctx, id, repo.Acl)
+		if err != nil {
+			return nil, err
+		}
+		plan = append(plan, acl...)
+	}
+	return plan, nil
+}
+
+func (c *Client) planSqlViews(ctx context.Context, repo string, desired []SqlView) (Plan, error) {
+	if len(desired) == 0 {
+		return nil, nil
+	}
+
+	names, err := c.repository.SqlViews(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var plan Plan
+	for _, view := range desired {
+		if !slices.Contains(names, view.Name) {
+			plan = append(plan, c.createSqlView(repo, view))
+			continue
+		}
+
+		current, err := c.repository.SqlView(ctx, repo, view.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		var diff []string
+		if strings.TrimSpace(current.Query) != strings.TrimSpace(view.Query) {
+			diff = append(diff, "query")
+		}
+		if !sameJson(current.Columns, view.Columns) {
+			diff = append(diff, "columns")
+		}
+
+		if len(diff) > 0 {
+			plan = append(plan, Change{Action: ChangeUpdate, Resource: ResourceSqlView, Name: repo + "/" + view.Name, Diff: diff, apply: func(ctx context.Context) error {
+				return c.repository.UpdateSqlView(ctx, repo, view)
+			}})
+		}
+	}
+	return plan, nil
+}
+
+func (c *Client) createSqlView(repo string, view SqlView) Change {
+	return Change{Action: ChangeCreate, Resource: ResourceSqlView, Name: repo + "/" + view.Name, apply: func(ctx context.Context) error {
+		return c.repository.CreateSqlView(ctx, repo, view)
+	}}
+}
+
+func (c *Client) planAcl(ctx context.Context, repo string, desired *Policies) (Plan, error) {
+	if desired == nil {
+		return nil, nil
+	}
+
+	current, err := c.acl.List(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	if sameJson(current, *desired) || (len(current) == 0 && len(*desired) == 0) {
+		return nil, nil
+	}
+
+	diff := []string{fmt.Sprintf("policies: %d -> %d", len(current), len(*desired))}
+	return Plan{c.replaceAcl(repo, *desired, diff)}, nil
+}
+
+func (c *Client) replaceAcl(repo string, policies Policies, diff []string) Change {
+	return Change{Action: ChangeUpdate, Resource: ResourceAcl, Name: repo, Diff: diff, apply: func(ctx context.Context) error {
+		return c.acl.Replace(ctx, repo, policies)
+	}}
+}
+
+func (c *Client) planSparqlTemplates(ctx context.Context, repo string, desired []SparqlTemplate) (Plan, error) {
+	if len(desired) == 0 {
+		return nil, nil
+	}
+
+	ids, err := c.repository.SparqlTemplates(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var plan Plan
+	for _, template := range desired {
+		if !slices.Contains(ids, template.Id) {
+			plan = append(plan, c.createSparqlTemplate(repo, template))
+			continue
+		}
+
+		current, err := c.repository.SparqlTemplate(ctx, repo, template.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.TrimSpace(current.Query) != strings.TrimSpace(template.Query) {
+			plan = append(plan, Change{Action: ChangeUpdate, Resource: ResourceSparqlTemplate, Name: repo + "/" + template.Id, Diff: []string{"query"}, apply: func(ctx context.Context) error {
+				return c.repository.UpdateSparqlTemplates(ctx, repo, template)
+			}})
+		}
+	}
+	return plan, nil
+}
+
+func (c *Client) createSparqlTemplate(repo string, template SparqlTemplate) Change {
+	return Change{Action: ChangeCreate, Resource: ResourceSparqlTemplate, Name: repo + "/" + template.Id, apply: func(ctx context.Context) error {
+		return c.repository.CreateSparqlTemplates(ctx, repo, template)
+	}}
+}
+
+func (c *Client) planUsers(ctx context.Context, desired State) (Plan, error) {
+	if len(desired.Users) == 0 {
+		return nil, nil
+	}
+
+	users, err := c.security.Users(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[string]User{}
+	for _, u := range users {
+		existing[u.Username] = u
+	}
+
+	var plan Plan
+	for _, user := range desired.Users {
+		cur, ok := existing[user.Username]
+		if !ok {
+			plan = append(plan, Change{Action: ChangeCreate, Resource: ResourceUser, Name: user.Username, apply: func(ctx context.Context) error {
+				return c.security.CreateUser(ctx, user)
+			}})
+			continue
+		}
+
+		// passwords are only returned hashed and cannot be compared, they are only set when the user is created
+		var diff []string
+		if !sameSet(cur.GrantedAuthorities, user.GrantedAuthorities) {
+			diff = append(diff, fmt.Sprintf("grantedAuthorities: %v -> %v", sorted(cur.GrantedAuthorities), sorted(user.GrantedAuthorities)))
+		}
+		for _, key := range slices.Sorted(maps.Keys(user.AppSettings)) {
+			if cur.AppSettings[key] != user.AppSettings[key] {
+				diff = append(diff, fmt.Sprintf("appSettings.%s: %t -> %t", key, cur.AppSettings[key], user.AppSettings[key]))
+			}
+		}
+
+		if len(diff) > 0 {
+			update := user
+			update.Password = ""
+			if update.AppSettings == nil {
+				update.AppSettings = cur.AppSettings
+			}
+			plan = append(plan, Change{Action: ChangeUpdate, Resource: ResourceUser, Name: user.Username, Diff: diff, apply: func(ctx context.Context) error {
+				return c.security.UpdateUser(ctx, update)
+			}})
+		}
+	}
+	return plan, nil
+}
+
+func (c *Client) planCustomRoles(ctx context.Context, desired State) (Plan, error) {
+	if desired.CustomRoles == nil {
+		return nil, nil
+	}
+
+	current, err := c.security.GetCustomRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var diff []string
+	for _, role := range slices.Sorted(maps.Keys(desired.CustomRoles)) {
+		if cur, ok := current[role]; !ok || !sameSet(cur, desired.CustomRoles[role]) {
+			diff = append(diff, fmt.Sprintf("%s: %v -> %v", role, sorted(cur), sorted(desired.CustomRoles[role])))
+		}
+	}
+	for _, role := range slices.Sorted(maps.Keys(current)) {
+		if _, ok := desired.CustomRoles[role]; !ok {
+			diff = append(diff, fmt.Sprintf("%s: removed", role))
+		}
+	}
+
+	if len(diff) == 0 {
+		return nil, nil
+	}
+
+	roles := desired.CustomRoles
+	return Plan{{Action: ChangeUpdate, Resource: ResourceCustomRoles, Name: "*", Diff: diff, apply: func(ctx context.Context) error {
+		return c.security.ReplaceCustomRoles(ctx, roles)
+	}}}, nil
+}
+
+func (c *Client) planFreeAccess(ctx context.Context, desired State) (Plan, error) {
+	if desired.FreeAccess == nil {
+		return nil, nil
+	}
+
+	current, err := c.security.FreeAccess(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	access := *desired.FreeAccess
+	var diff []string
+	if current.Enabled != access.Enabled {
+		diff = append(diff, fmt.Sprintf("enabled: %t -> %t", current.Enabled, access.Enabled))
+	}
+	if !sameSet(current.Authorities, access.Authorities) {
+		diff = append(diff, fmt.Sprintf("authorities: %v -> %v", sorted(current.Authorities), sorted(access.Authorities)))
+	}
+	for _, key := range slices.Sorted(maps.Keys(access.AppSettings)) {
+		if current.AppSettings[key] != access.AppSettings[key] {
+			diff = append(diff, fmt.Sprintf("appSettings.%s: %t -> %t", key, current.AppSettings[key], access.AppSettings[key]))
+		}
+	}
+
+	if len(diff) == 0 {
+		return nil, nil
+	}
+	return Plan{{Action: ChangeUpdate, Resource: ResourceFreeAccess, Name: "*", Diff: diff, apply: func(ctx context.Context) error {
+		return c.security.ConfigureFreeAccess(ctx, access)
+	}}}, nil
+}
+
+func (c *Client) planSavedQueries(ctx context.Context, desired State) (Plan, error) {
+	if len(desired.SavedQueries) == 0 {
+		return nil, nil
+	}
+
+	queries, err := c.savedQueries.SavedQueries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[string]SavedQuery{}
+	for _, q := range queries {
+		existing[q.Name] = q
+	}
+
+	var plan Plan
+	for _, query := range desired.SavedQueries {
+		cur, ok := existing[query.Name]
+		if !ok {
+			plan = append(plan, Change{Action: ChangeCreate, Resource: ResourceSavedQuery, Name: query.Name, apply: func(ctx context.Context) error {
+				return c.savedQueries.CreateSavedQueries(ctx, query)
+			}})
+			continue
+		}
+
+		var diff []string
+		if strings.TrimSpace(cur.Body) != strings.TrimSpace(query.Body) {
+			diff = append(diff, "body")
+		}
+		if cur.Shared != query.Shared {
+			diff = append(diff, fmt.Sprintf("shared: %t -> %t", cur.Shared, query.Shared))
+		}
+
+		if len(diff) > 0 {
+			plan = append(plan, Change{Action: ChangeUpdate, Resource: ResourceSavedQuery, Name: query.Name, Diff: diff, apply: func(ctx context.Context) error {
+				return c.savedQueries.UpdateSavedQueries(ctx, query.Name, query)
+			}})
+		}
+	}
+	return plan, nil
+}
+
+// diffField appends a "name: current -> desired" entry when a desired, non-empty value differs from the current one.
+func diffField(diff []string, name, current, desired string) []string {
+	if desired == "" || current == desired {
+		return diff
+	}
+	return append(diff, fmt.Sprintf("%s: %q -> %q", name, current, desired))
+}
+
+func sameJson(a, b any) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ja, jb)
+}
+
+func sameSet(a, b []string) bool {
+	return slices.Equal(sorted(a), sorted(b))
+}
+
+func sorted(s []string) []string {
+	c := slices.Clone(s)
+	sort.Strings(c)
+	return slices.Compact(c)
+}
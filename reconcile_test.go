@@ -0,0 +1,102 @@
+package graphdb
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const reconcileState = `
+repositories:
+  - config:
+      id: existing
+      params:
+        ruleset:
+          value: owl2-rl
+        queryTimeout:
+          value: 0
+  - config:
+      id: missing
+      type: graphdb
+    sparqlTemplates:
+      - templateID: http://example.org/template
+        query: DELETE WHERE { ?s ?p ?o }
+users:
+  - username: reader
+    grantedAuthorities: [ROLE_USER, READ_REPO_existing]
+customRoles:
+  custom_readers: [reader]
+savedQueries:
+  - name: all
+    body: SELECT * WHERE { ?s ?p ?o }
+`
+
+func TestClient_Reconcile(t *testing.T) {
+	state, err := LoadState(strings.NewReader(reconcileState))
+	if err != nil {
+		t.Fatalf("failed to load state: %v", err)
+	}
+
+	var applied []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			body, _ := io.ReadAll(r.Body)
+			applied = append(applied, r.Method+" "+r.URL.Path+" "+string(body))
+			w.WriteHeader(map[string]int{http.MethodPost: http.StatusCreated, http.MethodPut: http.StatusOK}[r.Method])
+			return
+		}
+
+		var v any
+		switch r.URL.Path {
+		case PathRepositories:
+			v = []RepositoryInfo{{Id: "existing"}}
+		case "/rest/repositories/existing":
+			v = map[string]any{"id": "existing", "params": map[string]any{
+				"ruleset":      map[string]any{"name": "ruleset", "value": "rdfsplus-optimized"},
+				"queryTimeout": map[string]any{"name": "queryTimeout", "value": 0},
+			}}
+		case PathSecurityUsers:
+			v = []User{{Username: "reader", GrantedAuthorities: []string{"READ_REPO_existing", "ROLE_USER"}}}
+		case PathCustomRoles:
+			v = map[string][]string{"custom_old": {"admin"}}
+		case PathSavedQueries:
+			v = []SavedQuery{{Name: "all", Body: "SELECT * WHERE { ?s ?p ?o }"}}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(v)
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+	plan, err := client.Plan(context.Background(), state)
+	if err != nil {
+		t.Fatalf("failed to plan: %v", err)
+	}
+
+	expected := `update repository existing (ruleset: "rdfsplus-optimized" -> "owl2-rl")
+create repository missing
+create sparql_template missing/http://example.org/template
+update custom_roles * (custom_readers: [] -> [reader], custom_old: removed)
+`
+	if plan.String() != expected {
+		t.Errorf("unexpected plan:\n%s", plan)
+	}
+
+	if len(applied) != 0 {
+		t.Fatalf("planning should not change anything, got %v", applied)
+	}
+
+	if _, err = client.Reconcile(context.Background(), state); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	if len(applied) != 4 || !strings.HasPrefix(applied[0], "PUT /rest/repositories/existing ") || !strings.Contains(applied[0], `"owl2-rl"`) {
+		t.Errorf("unexpected requests: %v", applied)
+	}
+}
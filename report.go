@@ -2,7 +2,6 @@ package graphdb
 
 import (
 	"context"
-	"errors"
 	"io"
 	"net/http"
 	"strconv"
@@ -34,7 +33,7 @@ func (r *ReportClient) Generate(ctx context.Context, config ...RequestConfig) (b
 	var accepted bool
 	return accepted, r.client.post(ctx, PathReport, nil, func(resp *http.Response) error {
 		if resp.StatusCode == http.StatusInternalServerError {
-			return errors.New("report: internal server error")
+			return NewAPIError("report", resp)
 		}
 
 		accepted = true
@@ -68,11 +67,8 @@ func (r *ReportClient) Status(ctx context.Context, config ...RequestConfig) (Rep
 
 func (r *ReportClient) Download(ctx context.Context, consumer func(filename string, r io.Reader) error, config ...RequestConfig) error {
 	return r.client.get(ctx, PathReport, func(resp *http.Response) error {
-		if resp.StatusCode != http.StatusOK {
-			all, err := io.ReadAll(resp.Body)
-			if err != nil {
-				return errors.Join(errors.New("report"), errors.New(string(all)), err)
-			}
+		if err := ErrNotStatus(http.StatusOK, "report", resp); err != nil {
+			return err
 		}
 
 		filename, err := extractFilename(resp.Header.Get("content-disposition"))
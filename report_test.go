@@ -2,7 +2,7 @@ package graphdb
 
 import (
 	"context"
-	"go-graphdb/testenv"
+	"github.com/yaskoo/go-graphdb/testenv"
 	"io"
 	"os"
 	"testing"
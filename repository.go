@@ -1,12 +1,16 @@
 package graphdb
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	fmt "fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -14,8 +18,13 @@ const (
 	PathRepository                    = PathRepositories + "/%s"
 	PathRepositorySize                = PathRepository + "/size"
 	PathRepositoryRestart             = PathRepository + "/restart"
+	PathRepositoryConfigTurtle        = PathRepository + "/download-ttl"
 	PathRepositoryImport              = PathRepository + "/import"
 	PathRepositoryImportServer        = PathRepositoryImport + "/server"
+	PathRepositoryImportUpload        = PathRepositoryImport + "/upload"
+	PathRepositoryImportUploadText    = PathRepositoryImportUpload + "/text"
+	PathRepositoryImportUploadUrl     = PathRepositoryImportUpload + "/url"
+	PathRepositoryImportUploadFile    = PathRepositoryImportUpload + "/file"
 	PathRepositorySparqlTemplates     = PathRepository + "/sparql-templates"
 	PathRepositorySparqlTemplatesExec = PathRepository + "/sparql-templates/execute"
 	PathRepositorySparqlTemplatesConf = PathRepository + "/sparql-templates/configuration"
@@ -59,6 +68,14 @@ type ParserSettings struct {
 	ContextLink               string `json:"contextLink"`
 }
 
+const (
+	RepositoryStateInactive   = "INACTIVE"
+	RepositoryStateStarting   = "STARTING"
+	RepositoryStateRunning    = "RUNNING"
+	RepositoryStateRestarting = "RESTARTING"
+	RepositoryStateStopping   = "STOPPING"
+)
+
 type RepositoryInfo struct {
 	Id          string `json:"id"`
 	Title       string `json:"title"`
@@ -100,6 +117,29 @@ type IntString struct {
 	s *string
 }
 
+// NewIntString creates a string valued IntString.
+func NewIntString(s string) *IntString {
+	return &IntString{s: &s}
+}
+
+// NewIntStringInt creates an int valued IntString.
+func NewIntStringInt(i int) *IntString {
+	return &IntString{i: &i}
+}
+
+// String returns the value as a string, regardless of whether it is an int or a string.
+func (is *IntString) String() string {
+	switch {
+	case is == nil:
+		return ""
+	case is.s != nil:
+		return *is.s
+	case is.i != nil:
+		return strconv.Itoa(*is.i)
+	}
+	return ""
+}
+
 func (is *IntString) UnmarshalJSON(data []byte) error {
 	var s string
 	if err := json.Unmarshal(data, &s); err == nil {
@@ -137,25 +177,46 @@ type RepositoryClient struct {
 
 func (r *RepositoryClient) Infos(ctx context.Context, conf ...RequestConfig) ([]RepositoryInfo, error) {
 	var infos []RepositoryInfo
-	rh := CombinedResponseHandler(ExpectStatusCode(http.StatusOK), UnmarshalJson(&infos))
+	rh := CombinedResponseHandler(ExpectStatusCodeFor("repo", http.StatusOK), UnmarshalJson(&infos))
 	return infos, r.client.get(ctx, PathRepositories, rh, conf...)
 }
 
 func (r *RepositoryClient) Size(ctx context.Context, id string, conf ...RequestConfig) (RepositorySize, error) {
 	var size RepositorySize
-	rh := CombinedResponseHandler(ExpectStatusCode(http.StatusOK), UnmarshalJson(&size))
+	rh := CombinedResponseHandler(ExpectStatusCodeFor("repo", http.StatusOK), UnmarshalJson(&size))
 	return size, r.client.get(ctx, fmt.Sprintf(PathRepositorySize, id), rh, conf...)
 }
 
+// Config returns the configuration of an existing repository, in the same form accepted by Create and Edit.
+func (r *RepositoryClient) Config(ctx context.Context, id string, conf ...RequestConfig) (RepositoryConfig, error) {
+	var config RepositoryConfig
+	rh := CombinedResponseHandler(ExpectStatusCodeFor("repo", http.StatusOK), UnmarshalJson(&config))
+	return config, r.client.get(ctx, fmt.Sprintf(PathRepository, id), rh, conf...)
+}
+
+// ConfigTurtle returns the RDF4J Turtle configuration of an existing repository, which can be passed back to Create
+// as the config part of MultipartFormData.
+func (r *RepositoryClient) ConfigTurtle(ctx context.Context, id string, conf ...RequestConfig) (string, error) {
+	var config string
+	return config, r.client.get(ctx, fmt.Sprintf(PathRepositoryConfigTurtle, id), func(resp *http.Response) error {
+		if err := ErrNotStatus(http.StatusOK, "repo", resp); err != nil {
+			return err
+		}
+
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("repo: %w", err)
+		}
+		config = string(b)
+		return nil
+	}, conf...)
+}
+
 func (r *RepositoryClient) Create(ctx context.Context, config RequestConfig, other ...RequestConfig) error {
 	rc := []RequestConfig{config}
 	rc = append(rc, other...)
 	return r.client.post(ctx, PathRepositories, nil, func(resp *http.Response) error {
-		if resp.StatusCode != http.StatusCreated {
-			b, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("repo: %s", string(b))
-		}
-		return nil
+		return ErrNotStatus(http.StatusCreated, "repo", resp)
 	}, rc...)
 }
 
@@ -163,35 +224,90 @@ func (r *RepositoryClient) Edit(ctx context.Context, id string, config Repositor
 	rc := []RequestConfig{JsonBody(config)}
 	rc = append(rc, other...)
 	return r.client.put(ctx, fmt.Sprintf(PathRepository, id), nil, func(resp *http.Response) error {
-		if resp.StatusCode != http.StatusCreated {
-			b, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("repo: %s", string(b))
-		}
-		return nil
+		return ErrNotOneOfStatus("repo", resp, http.StatusOK, http.StatusCreated)
 	}, rc...)
 }
 
 func (r *RepositoryClient) Delete(ctx context.Context, id string, conf ...RequestConfig) error {
-	rh := CombinedResponseHandler(ExpectStatusCode(http.StatusOK))
+	rh := CombinedResponseHandler(ExpectStatusCodeFor("repo", http.StatusOK))
 	return r.client.delete(ctx, fmt.Sprintf(PathRepository, id), nil, rh, conf...)
 }
 
 func (r *RepositoryClient) Restart(ctx context.Context, id string, conf ...RequestConfig) error {
-	rh := CombinedResponseHandler(ExpectOneOfStatusCode(http.StatusOK, http.StatusAccepted))
+	rh := CombinedResponseHandler(ExpectOneOfStatusCodeFor("repo", http.StatusOK, http.StatusAccepted))
 	return r.client.post(ctx, fmt.Sprintf(PathRepositoryRestart, id), nil, rh, conf...)
 }
 
+// Info returns the information of a single repository, or ErrNotFound if it does not exist.
+func (r *RepositoryClient) Info(ctx context.Context, id string, conf ...RequestConfig) (RepositoryInfo, error) {
+	infos, err := r.Infos(ctx, conf...)
+	if err != nil {
+		return RepositoryInfo{}, err
+	}
+
+	for _, info := range infos {
+		if info.Id == id {
+			return info, nil
+		}
+	}
+	return RepositoryInfo{}, fmt.Errorf("repo %s: %w", id, ErrNotFound)
+}
+
+// WaitState polls the repository until its state is one of the given states, or the context is done.
+// The poll interval and backoff are configured with WithPolling.
+func (r *RepositoryClient) WaitState(ctx context.Context, id string, states ...string) (RepositoryInfo, error) {
+	p := newPoller(r.client.polling)
+	for {
+		info, err := r.Info(ctx, id)
+		if err != nil {
+			return info, err
+		}
+
+		for _, state := range states {
+			if info.State == state {
+				return info, nil
+			}
+		}
+
+		if err = p.wait(ctx); err != nil {
+			return info, fmt.Errorf("repo %s: waiting for %v, last state %s: %w", id, states, info.State, err)
+		}
+	}
+}
+
+// CreateAndWait creates a repository and returns once it is initialized and answers queries.
+// Repositories are initialized lazily, so a query is sent until it succeeds before waiting for the running state.
+func (r *RepositoryClient) CreateAndWait(ctx context.Context, id string, config RequestConfig, other ...RequestConfig) error {
+	if err := r.Create(ctx, config, other...); err != nil {
+		return err
+	}
+
+	p := newPoller(r.client.polling)
+	for {
+		_, err := r.client.rdf4j.Ask(ctx, id, "ASK {}")
+		if err == nil {
+			break
+		}
+
+		if waitErr := p.wait(ctx); waitErr != nil {
+			return fmt.Errorf("repo %s: waiting to be queryable: %w", id, errors.Join(err, waitErr))
+		}
+	}
+
+	_, err := r.WaitState(ctx, id, RepositoryStateRunning)
+	return err
+}
+
 func (r *RepositoryClient) ServerFiles(ctx context.Context, id string, conf ...RequestConfig) ([]ImportSettings, error) {
 	var available []ImportSettings
 	return available, r.client.get(ctx, fmt.Sprintf(PathRepositoryImportServer, id), func(resp *http.Response) error {
-		if resp.StatusCode != http.StatusOK {
-			b, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("import server: %s", string(b))
+		if err := ErrNotStatus(http.StatusOK, "import_server", resp); err != nil {
+			return err
 		}
 
 		err := json.NewDecoder(resp.Body).Decode(&available)
 		if err != nil {
-			return fmt.Errorf("import server: %w", err)
+			return fmt.Errorf("import_server: %w", err)
 		}
 		return nil
 	}, conf...)
@@ -203,26 +319,86 @@ func (r *RepositoryClient) ImportServerFiles(ctx context.Context, id string, fil
 		"importSettings": settings,
 	}))
 	return r.client.post(ctx, fmt.Sprintf(PathRepositoryImportServer, id), nil, func(resp *http.Response) error {
-		if resp.StatusCode != http.StatusAccepted {
-			b, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("import server: %s", string(b))
-		}
-		return nil
+		return ErrNotStatus(http.StatusAccepted, "import_server", resp)
 	}, conf...)
 }
 
 func (r *RepositoryClient) CancelServerFile(ctx context.Context, id string, file string, conf ...RequestConfig) error {
 	conf = append(conf, Query("name", file))
 	return r.client.delete(ctx, fmt.Sprintf(PathRepositoryImportServer, id), nil, func(resp *http.Response) error {
-		if resp.StatusCode != http.StatusAccepted {
-			b, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("import server: %s", string(b))
+		return ErrNotStatus(http.StatusAccepted, "import_server", resp)
+	}, conf...)
+}
+
+// ImportText imports an RDF snippet. The snippet is tracked alongside the uploaded files under settings.Name.
+func (r *RepositoryClient) ImportText(ctx context.Context, id string, text string, settings ImportSettings, conf ...RequestConfig) error {
+	settings.Type = "text"
+	settings.Data = text
+	if settings.Name == "" {
+		settings.Name = fmt.Sprintf("Text snippet %s", time.Now().Format(time.RFC3339))
+	}
+
+	conf = append(conf, JsonBody(settings))
+	return r.client.post(ctx, fmt.Sprintf(PathRepositoryImportUploadText, id), nil, importUploadResponseHandler, conf...)
+}
+
+// ImportURL asks the server to fetch and import RDF data from the given URL.
+func (r *RepositoryClient) ImportURL(ctx context.Context, id string, url string, settings ImportSettings, conf ...RequestConfig) error {
+	settings.Type = "url"
+	settings.Data = url
+	if settings.Name == "" {
+		settings.Name = url
+	}
+
+	conf = append(conf, JsonBody(settings))
+	return r.client.post(ctx, fmt.Sprintf(PathRepositoryImportUploadUrl, id), nil, importUploadResponseHandler, conf...)
+}
+
+// ImportUpload uploads the content of the reader as a file and imports it. The format is detected from the filename,
+// unless settings.Format is set.
+func (r *RepositoryClient) ImportUpload(ctx context.Context, id string, filename string, file io.Reader, settings ImportSettings, conf ...RequestConfig) error {
+	settings.Type = "file"
+	if settings.Name == "" {
+		settings.Name = filename
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("import_upload: %w", err)
+	}
+
+	conf = append(conf, MultipartFormData(
+		Part{Key: "importSettings", Type: "application/json", Value: bytes.NewReader(data)},
+		Part{Key: "file", Filename: filename, Value: file},
+	))
+	return r.client.post(ctx, fmt.Sprintf(PathRepositoryImportUploadFile, id), nil, importUploadResponseHandler, conf...)
+}
+
+// UploadFiles lists the text snippets, URLs and files imported through ImportText, ImportURL and ImportUpload.
+func (r *RepositoryClient) UploadFiles(ctx context.Context, id string, conf ...RequestConfig) ([]ImportSettings, error) {
+	var available []ImportSettings
+	return available, r.client.get(ctx, fmt.Sprintf(PathRepositoryImportUpload, id), func(resp *http.Response) error {
+		if err := ErrNotStatus(http.StatusOK, "import_upload", resp); err != nil {
+			return err
+		}
+
+		err := json.NewDecoder(resp.Body).Decode(&available)
+		if err != nil {
+			return fmt.Errorf("import_upload: %w", err)
 		}
 		return nil
 	}, conf...)
 }
 
-// todo: imports from text, url, and upload are hidden... why?
+// CancelUploadFile cancels a running upload import, or removes a finished one from the UploadFiles list.
+func (r *RepositoryClient) CancelUploadFile(ctx context.Context, id string, name string, conf ...RequestConfig) error {
+	conf = append(conf, Query("name", name))
+	return r.client.delete(ctx, fmt.Sprintf(PathRepositoryImportUpload, id), nil, importUploadResponseHandler, conf...)
+}
+
+func importUploadResponseHandler(resp *http.Response) error {
+	return ErrNotOneOfStatus("import_upload", resp, http.StatusOK, http.StatusAccepted)
+}
 
 func (r *RepositoryClient) SparqlTemplates(ctx context.Context, repo string, conf ...RequestConfig) ([]string, error) {
 	var ids []string
@@ -239,13 +415,13 @@ func (r *RepositoryClient) SparqlTemplates(ctx context.Context, repo string, con
 }
 
 type SparqlTemplate struct {
-	Id    string `json:"id,omitempty"`
+	Id    string `json:"templateID,omitempty"`
 	Query string `json:"query,omitempty"`
 }
 
-func (r *RepositoryClient) CreateSparqlTemplates(ctx context.Context, repo, template SparqlTemplate, conf ...RequestConfig) error {
+func (r *RepositoryClient) CreateSparqlTemplates(ctx context.Context, repo string, template SparqlTemplate, conf ...RequestConfig) error {
 	conf = append(conf, JsonBody(template))
-	return r.client.put(ctx, fmt.Sprintf(PathRepositorySparqlTemplates, repo), nil, func(resp *http.Response) error {
+	return r.client.post(ctx, fmt.Sprintf(PathRepositorySparqlTemplates, repo), nil, func(resp *http.Response) error {
 		return ErrNotStatus(http.StatusCreated, "sparql_templates", resp)
 	}, conf...)
 }
@@ -281,7 +457,7 @@ func (r *RepositoryClient) SparqlTemplate(ctx context.Context, repo, template st
 
 	var v SparqlTemplate
 	return v, r.client.get(ctx, fmt.Sprintf(PathRepositorySparqlTemplatesConf, repo), func(resp *http.Response) error {
-		if err := ErrNotStatus(http.StatusNoContent, "sparql_templates", resp); err != nil {
+		if err := ErrNotStatus(http.StatusOK, "sparql_templates", resp); err != nil {
 			return err
 		}
 		if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
@@ -292,9 +468,9 @@ func (r *RepositoryClient) SparqlTemplate(ctx context.Context, repo, template st
 }
 
 type SqlView struct {
-	Name    string    `json:"name,omitempty"`
-	Query   string    `json:"query,omitempty"`
-	Columns SqlColumn `json:"columns,omitempty"`
+	Name    string      `json:"name,omitempty"`
+	Query   string      `json:"query,omitempty"`
+	Columns []SqlColumn `json:"columns,omitempty"`
 }
 
 type SqlColumn struct {
@@ -5,11 +5,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"go-graphdb/testenv"
+	"github.com/yaskoo/go-graphdb/testenv"
 	"io"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -60,6 +61,40 @@ func TestRepository_CreateEditDelete(t *testing.T) {
 	})
 }
 
+func TestRepository_Config(t *testing.T) {
+	testenv.WithEnv(t, func(url string) {
+		client := New(url)
+
+		id, err := createRepository(t, client)
+		if err != nil {
+			t.Fatalf("failed to create repository: %v", err)
+		}
+
+		config, err := client.Repositories().Config(context.Background(), id)
+		if err != nil {
+			t.Fatalf("failed to get repository config: %v", err)
+		}
+
+		if config.Id != id || config.Params["ruleset"].Value.String() != "rdfsplus-optimized" {
+			t.Errorf("unexpected repository config: %v", config)
+		}
+
+		ttl, err := client.Repositories().ConfigTurtle(context.Background(), id)
+		if err != nil {
+			t.Fatalf("failed to get repository turtle config: %v", err)
+		}
+
+		if !strings.Contains(ttl, id) {
+			t.Errorf("expected turtle config to contain the repository id:\n%s", ttl)
+		}
+
+		config.Id = uuid.New().String()
+		if err = client.Repositories().Create(context.Background(), JsonBody(config)); err != nil {
+			t.Errorf("failed to create repository from round-tripped config: %v", err)
+		}
+	})
+}
+
 func TestRepository_Size(t *testing.T) {
 	testenv.WithEnv(t, func(url string) {
 		client := New(url)
@@ -101,6 +136,73 @@ func TestRepository_Restart(t *testing.T) {
 	})
 }
 
+func TestRepository_ImportUpload(t *testing.T) {
+	testenv.WithEnv(t, func(url string) {
+		client := New(url)
+
+		id, err := createRepository(t, client)
+		if err != nil {
+			t.Fatalf("failed to create repository: %v", err)
+		}
+
+		data := `<http://example.org/s> <http://example.org/p> "o" .`
+		err = client.Repositories().ImportText(context.Background(), id, data, ImportSettings{Name: "snippet", Format: "text/turtle"})
+		if err != nil {
+			t.Fatalf("failed to import text: %v", err)
+		}
+
+		err = client.Repositories().ImportUpload(context.Background(), id, "data.nt", strings.NewReader(data), ImportSettings{})
+		if err != nil {
+			t.Fatalf("failed to import upload: %v", err)
+		}
+
+		files, err := client.Repositories().UploadFiles(context.Background(), id)
+		if err != nil {
+			t.Fatalf("failed to list uploaded files: %v", err)
+		}
+
+		if len(files) != 2 {
+			t.Fatalf("expected 2 uploaded files, got %d", len(files))
+		}
+
+		if err = client.Repositories().CancelUploadFile(context.Background(), id, "snippet"); err != nil {
+			t.Errorf("failed to remove uploaded text: %v", err)
+		}
+	})
+}
+
+func TestRepository_CreateAndWait(t *testing.T) {
+	testenv.WithEnv(t, func(url string) {
+		client := New(url, WithPolling(Polling{Interval: 100 * time.Millisecond}))
+
+		config, err := repositoryJsonConfig()
+		if err != nil {
+			t.Fatal("cannot read default repository config")
+		}
+		config.Id = uuid.New().String()
+
+		if err = client.Repositories().CreateAndWait(context.Background(), config.Id, JsonBody(config)); err != nil {
+			t.Fatalf("failed to create repository: %v", err)
+		}
+
+		if err = client.Repositories().Restart(context.Background(), config.Id); err != nil {
+			t.Fatalf("failed to restart repository: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		info, err := client.Repositories().WaitState(ctx, config.Id, RepositoryStateRunning)
+		if err != nil {
+			t.Fatalf("repository did not start after restart: %v", err)
+		}
+
+		if info.State != RepositoryStateRunning {
+			t.Errorf("unexpected repository state %s", info.State)
+		}
+	})
+}
+
 func createRepository(t *testing.T, client *Client) (string, error) {
 	config, err := repositoryJsonConfig()
 	if err != nil {
@@ -0,0 +1,290 @@
+package graphdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yaskoo/go-graphdb/rdf"
+)
+
+const (
+	RulesetEmpty             = "empty"
+	RulesetRDFS              = "rdfs"
+	RulesetRDFSOptimized     = "rdfs-optimized"
+	RulesetRDFSPlus          = "rdfsplus"
+	RulesetRDFSPlusOptimized = "rdfsplus-optimized"
+	RulesetOWLHorst          = "owl-horst"
+	RulesetOWLHorstOptimized = "owl-horst-optimized"
+	RulesetOWLMax            = "owl-max"
+	RulesetOWLMaxOptimized   = "owl-max-optimized"
+	RulesetOWL2QL            = "owl2-ql"
+	RulesetOWL2QLOptimized   = "owl2-ql-optimized"
+	RulesetOWL2RL            = "owl2-rl"
+	RulesetOWL2RLOptimized   = "owl2-rl-optimized"
+)
+
+var rulesets = map[string]bool{
+	RulesetEmpty: true, RulesetRDFS: true, RulesetRDFSOptimized: true, RulesetRDFSPlus: true,
+	RulesetRDFSPlusOptimized: true, RulesetOWLHorst: true, RulesetOWLHorstOptimized: true, RulesetOWLMax: true,
+	RulesetOWLMaxOptimized: true, RulesetOWL2QL: true, RulesetOWL2QLOptimized: true, RulesetOWL2RL: true,
+	RulesetOWL2RLOptimized: true,
+}
+
+// repositoryParam describes how a GraphDB sail parameter appears in the JSON and in the Turtle configuration.
+type repositoryParam struct {
+	label     string
+	predicate string
+	number    bool
+}
+
+var repositoryParams = map[string]repositoryParam{
+	"ruleset":                                {label: "Ruleset", predicate: "ruleset"},
+	"disableSameAs":                          {label: "Disable owl:sameAs", predicate: "disable-sameAs"},
+	"checkForInconsistencies":                {label: "Enable consistency checks", predicate: "check-for-inconsistencies"},
+	"entityIdSize":                           {label: "Entity ID size", predicate: "entity-id-size"},
+	"entityIndexSize":                        {label: "Entity index size", predicate: "entity-index-size"},
+	"enableContextIndex":                     {label: "Enable context index", predicate: "enable-context-index"},
+	"enablePredicateList":                    {label: "Enable predicate list index", predicate: "enablePredicateList"},
+	"enableLiteralIndex":                     {label: "Enable literal index", predicate: "enable-literal-index"},
+	"enableFtsIndex":                         {label: "Enable full-text search (FTS) index", predicate: "enable-fts-index"},
+	"inMemoryLiteralProperties":              {label: "Cache literal language tags", predicate: "in-memory-literal-properties"},
+	"queryTimeout":                           {label: "Query timeout (seconds)", predicate: "query-timeout", number: true},
+	"queryLimitResults":                      {label: "Limit query results", predicate: "query-limit-results", number: true},
+	"throwQueryEvaluationExceptionOnTimeout": {label: "Throw exception on query timeout", predicate: "throw-QueryEvaluationException-on-timeout"},
+	"readOnly":                               {label: "Read-only", predicate: "read-only"},
+	"baseURL":                                {label: "Base URL", predicate: "base-URL"},
+	"defaultNS":                              {label: "Default namespaces for imports(';' delimited)", predicate: "defaultNS"},
+	"imports":                                {label: "Imported RDF files(';' delimited)", predicate: "imports"},
+	"repositoryType":                         {label: "Repository type", predicate: "repository-type"},
+	"storageFolder":                          {label: "Storage folder", predicate: "storage-folder"},
+}
+
+// RepositoryConfigBuilder builds the configuration of a GraphDB repository from typed options.
+// It starts from GraphDB's defaults, and validation errors are reported by Build and Turtle.
+type RepositoryConfigBuilder struct {
+	id       string
+	title    string
+	location string
+	params   map[string]string
+	errs     []error
+}
+
+func NewRepositoryConfig(id string) *RepositoryConfigBuilder {
+	b := &RepositoryConfigBuilder{
+		id: id,
+		params: map[string]string{
+			"ruleset":                                RulesetRDFSPlusOptimized,
+			"disableSameAs":                          "true",
+			"checkForInconsistencies":                "false",
+			"entityIdSize":                           "32",
+			"entityIndexSize":                        "10000000",
+			"enableContextIndex":                     "false",
+			"enablePredicateList":                    "true",
+			"enableLiteralIndex":                     "true",
+			"enableFtsIndex":                         "false",
+			"inMemoryLiteralProperties":              "true",
+			"queryTimeout":                           "0",
+			"queryLimitResults":                      "0",
+			"throwQueryEvaluationExceptionOnTimeout": "false",
+			"readOnly":                               "false",
+			"baseURL":                                "http://example.org/owlim#",
+			"defaultNS":                              "",
+			"imports":                                "",
+			"repositoryType":                         "file-repository",
+			"storageFolder":                          "storage",
+		},
+	}
+
+	if id == "" || strings.ContainsAny(id, " /\\?#") {
+		b.errs = append(b.errs, fmt.Errorf("repository config: invalid id %q", id))
+	}
+	return b
+}
+
+func (b *RepositoryConfigBuilder) Title(title string) *RepositoryConfigBuilder {
+	b.title = title
+	return b
+}
+
+func (b *RepositoryConfigBuilder) Location(location string) *RepositoryConfigBuilder {
+	b.location = location
+	return b
+}
+
+// Ruleset sets one of the built-in rulesets, or the path to a custom .pie file.
+func (b *RepositoryConfigBuilder) Ruleset(ruleset string) *RepositoryConfigBuilder {
+	if !rulesets[ruleset] && !strings.HasSuffix(ruleset, ".pie") {
+		b.errs = append(b.errs, fmt.Errorf("repository config: unknown ruleset %q", ruleset))
+	}
+	return b.set("ruleset", ruleset)
+}
+
+func (b *RepositoryConfigBuilder) BaseURL(base string) *RepositoryConfigBuilder {
+	if u, err := url.Parse(base); err != nil || !u.IsAbs() {
+		b.errs = append(b.errs, fmt.Errorf("repository config: base url %q is not absolute", base))
+	}
+	return b.set("baseURL", base)
+}
+
+func (b *RepositoryConfigBuilder) EntityIndexSize(size int) *RepositoryConfigBuilder {
+	if size <= 0 {
+		b.errs = append(b.errs, fmt.Errorf("repository config: entity index size must be positive, got %d", size))
+	}
+	return b.set("entityIndexSize", strconv.Itoa(size))
+}
+
+// EntityIdSize sets the size of entity ids in bits, either 32 or 40.
+func (b *RepositoryConfigBuilder) EntityIdSize(bits int) *RepositoryConfigBuilder {
+	if bits != 32 && bits != 40 {
+		b.errs = append(b.errs, fmt.Errorf("repository config: entity id size must be 32 or 40, got %d", bits))
+	}
+	return b.set("entityIdSize", strconv.Itoa(bits))
+}
+
+// SameAs enables or disables owl:sameAs optimization.
+func (b *RepositoryConfigBuilder) SameAs(enabled bool) *RepositoryConfigBuilder {
+	return b.set("disableSameAs", strconv.FormatBool(!enabled))
+}
+
+func (b *RepositoryConfigBuilder) ContextIndex(enabled bool) *RepositoryConfigBuilder {
+	return b.set("enableContextIndex", strconv.FormatBool(enabled))
+}
+
+func (b *RepositoryConfigBuilder) PredicateList(enabled bool) *RepositoryConfigBuilder {
+	return b.set("enablePredicateList", strconv.FormatBool(enabled))
+}
+
+func (b *RepositoryConfigBuilder) LiteralIndex(enabled bool) *RepositoryConfigBuilder {
+	return b.set("enableLiteralIndex", strconv.FormatBool(enabled))
+}
+
+func (b *RepositoryConfigBuilder) FtsIndex(enabled bool) *RepositoryConfigBuilder {
+	return b.set("enableFtsIndex", strconv.FormatBool(enabled))
+}
+
+func (b *RepositoryConfigBuilder) ConsistencyChecks(enabled bool) *RepositoryConfigBuilder {
+	return b.set("checkForInconsistencies", strconv.FormatBool(enabled))
+}
+
+// QueryTimeout sets the query timeout, with a granularity of one second. Zero disables the timeout.
+func (b *RepositoryConfigBuilder) QueryTimeout(timeout time.Duration) *RepositoryConfigBuilder {
+	if timeout < 0 || timeout%time.Second != 0 {
+		b.errs = append(b.errs, fmt.Errorf("repository config: query timeout must be a non-negative number of seconds, got %s", timeout))
+	}
+	return b.set("queryTimeout", strconv.Itoa(int(timeout/time.Second)))
+}
+
+// QueryLimitResults limits the number of results returned by a query. Zero means unlimited.
+func (b *RepositoryConfigBuilder) QueryLimitResults(limit int) *RepositoryConfigBuilder {
+	if limit < 0 {
+		b.errs = append(b.errs, fmt.Errorf("repository config: query limit must not be negative, got %d", limit))
+	}
+	return b.set("queryLimitResults", strconv.Itoa(limit))
+}
+
+// ThrowQueryEvaluationExceptionOnTimeout makes queries fail on timeout instead of returning partial results.
+func (b *RepositoryConfigBuilder) ThrowQueryEvaluationExceptionOnTimeout(enabled bool) *RepositoryConfigBuilder {
+	return b.set("throwQueryEvaluationExceptionOnTimeout", strconv.FormatBool(enabled))
+}
+
+func (b *RepositoryConfigBuilder) ReadOnly(readOnly bool) *RepositoryConfigBuilder {
+	return b.set("readOnly", strconv.FormatBool(readOnly))
+}
+
+func (b *RepositoryConfigBuilder) StorageFolder(folder string) *RepositoryConfigBuilder {
+	if folder == "" {
+		b.errs = append(b.errs, errors.New("repository config: storage folder must not be empty"))
+	}
+	return b.set("storageFolder", folder)
+}
+
+// Imports sets the RDF files imported when the repository is initialized.
+func (b *RepositoryConfigBuilder) Imports(files ...string) *RepositoryConfigBuilder {
+	return b.set("imports", strings.Join(files, ";"))
+}
+
+// DefaultNamespaces sets the default namespaces of the files passed to Imports.
+func (b *RepositoryConfigBuilder) DefaultNamespaces(namespaces ...string) *RepositoryConfigBuilder {
+	return b.set("defaultNS", strings.Join(namespaces, ";"))
+}
+
+func (b *RepositoryConfigBuilder) set(name, value string) *RepositoryConfigBuilder {
+	b.params[name] = value
+	return b
+}
+
+// Build returns the configuration in the JSON form accepted by RepositoryClient.Create and Edit.
+func (b *RepositoryConfigBuilder) Build() (RepositoryConfig, error) {
+	if err := errors.Join(b.errs...); err != nil {
+		return RepositoryConfig{}, err
+	}
+
+	config := RepositoryConfig{
+		Id:       b.id,
+		Title:    b.title,
+		Type:     "graphdb",
+		Location: b.location,
+		Params:   map[string]RepositoryConfigParameter{},
+	}
+
+	for name, value := range b.params {
+		param := repositoryParams[name]
+		v := NewIntString(value)
+		if param.number {
+			i, _ := strconv.Atoi(value)
+			v = NewIntStringInt(i)
+		}
+		config.Params[name] = RepositoryConfigParameter{Name: name, Label: param.label, Value: v}
+	}
+	return config, nil
+}
+
+// Turtle returns the configuration in the RDF4J Turtle form, as used by the config part of a multipart create.
+func (b *RepositoryConfigBuilder) Turtle() (string, error) {
+	if err := errors.Join(b.errs...); err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(b.params))
+	for name := range b.params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString(`@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#>.
+@prefix rep: <http://www.openrdf.org/config/repository#>.
+@prefix sr: <http://www.openrdf.org/config/repository/sail#>.
+@prefix sail: <http://www.openrdf.org/config/sail#>.
+@prefix graphdb: <http://www.ontotext.com/config/graphdb#>.
+
+[] a rep:Repository ;
+`)
+	fmt.Fprintf(&sb, "    rep:repositoryID %s ;\n", rdf.NewLiteral(b.id))
+	fmt.Fprintf(&sb, "    rdfs:label %s ;\n", rdf.NewLiteral(b.title))
+	sb.WriteString(`    rep:repositoryImpl [
+        rep:repositoryType "graphdb:SailRepository" ;
+        sr:sailImpl [
+            sail:sailType "graphdb:Sail" ;
+`)
+	for _, name := range names {
+		fmt.Fprintf(&sb, "            graphdb:%s %s ;\n", repositoryParams[name].predicate, rdf.NewLiteral(b.params[name]))
+	}
+	sb.WriteString("        ]\n    ].\n")
+	return sb.String(), nil
+}
+
+// CreateFromConfig creates a repository from a configuration builder.
+func (r *RepositoryClient) CreateFromConfig(ctx context.Context, config *RepositoryConfigBuilder, other ...RequestConfig) error {
+	c, err := config.Build()
+	if err != nil {
+		return err
+	}
+	return r.Create(ctx, JsonBody(c), other...)
+}
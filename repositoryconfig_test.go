@@ -0,0 +1,75 @@
+package graphdb
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRepositoryConfigBuilder_Defaults(t *testing.T) {
+	expected, err := repositoryJsonConfig()
+	if err != nil {
+		t.Fatalf("cannot read default repository config: %v", err)
+	}
+
+	config, err := NewRepositoryConfig("test").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, param := range config.Params {
+		e, ok := expected.Params[name]
+		if !ok {
+			t.Errorf("unexpected parameter %s", name)
+			continue
+		}
+
+		if e.Label != param.Label || e.Value.String() != param.Value.String() {
+			t.Errorf("parameter %s: expected %s=%q, got %s=%q", name, e.Label, e.Value.String(), param.Label, param.Value.String())
+		}
+	}
+}
+
+func TestRepositoryConfigBuilder_Options(t *testing.T) {
+	b := NewRepositoryConfig("test").
+		Title("Test").
+		Ruleset(RulesetOWL2RL).
+		SameAs(true).
+		ContextIndex(true).
+		QueryTimeout(30 * time.Second).
+		ReadOnly(true)
+
+	config, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := config.Params["queryTimeout"].Value.MarshalJSON()
+	if err != nil || string(data) != "30" {
+		t.Errorf("expected numeric query timeout, got %s", data)
+	}
+
+	ttl, err := b.Turtle()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		`rep:repositoryID "test" ;`,
+		`rdfs:label "Test" ;`,
+		`graphdb:ruleset "owl2-rl" ;`,
+		`graphdb:disable-sameAs "false" ;`,
+		`graphdb:enable-context-index "true" ;`,
+		`graphdb:query-timeout "30" ;`,
+		`graphdb:read-only "true" ;`,
+	} {
+		if !strings.Contains(ttl, line) {
+			t.Errorf("expected turtle to contain %s:\n%s", line, ttl)
+		}
+	}
+
+	_, err = NewRepositoryConfig("bad id").Ruleset("owl3").EntityIdSize(64).QueryTimeout(time.Millisecond).Build()
+	if err == nil || strings.Count(err.Error(), "\n") != 3 {
+		t.Errorf("expected four validation errors, got %v", err)
+	}
+}
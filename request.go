@@ -8,6 +8,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
+	"slices"
 )
 
 type Part struct {
@@ -42,44 +43,108 @@ func Header(key, val string) RequestConfig {
 	}
 }
 
+// MultipartFormData streams the parts as a multipart/form-data body. The body can be replayed on retries only when all
+// part values implement io.ReaderAt and io.Seeker. Every attempt then reads the values from their position at the time
+// of the first request through its own io.SectionReader, so attempts never share a reader.
 func MultipartFormData(parts ...Part) RequestConfig {
 	return func(req *http.Request) {
+		sections := sectionParts(parts)
+		if sections != nil {
+			parts = sections()
+		}
+
 		pr, pw := io.Pipe()
 		writer := multipart.NewWriter(pw)
+		go writeParts(pw, writer, parts)
+
+		req.Header.Set("content-type", writer.FormDataContentType())
+		req.Body = pr
+		req.GetBody = nil
+		if sections == nil {
+			return
+		}
 
-		go func() {
-			defer pw.Close()
-
-			for _, p := range parts {
-				partHeader := textproto.MIMEHeader{}
-				if p.Filename != "" {
-					partHeader.Set("content-disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, p.Key, p.Filename))
-				} else {
-					partHeader.Set("content-disposition", fmt.Sprintf(`form-data; name="%s";`, p.Key))
-				}
-				partHeader.Set("content-type", p.ContentType())
-
-				part, err := writer.CreatePart(partHeader)
-				if err != nil {
-					_ = pw.CloseWithError(err)
-					return
-				}
-
-				_, err = io.Copy(part, p.Value)
-				if err != nil {
-					_ = pw.CloseWithError(err)
-					return
-				}
+		boundary := writer.Boundary()
+		req.GetBody = func() (io.ReadCloser, error) {
+			pr, pw := io.Pipe()
+			writer := multipart.NewWriter(pw)
+			if err := writer.SetBoundary(boundary); err != nil {
+				return nil, err
 			}
+			go writeParts(pw, writer, sections())
+			return pr, nil
+		}
+	}
+}
 
-			_ = writer.Close()
-		}()
+// sectionParts returns a function creating copies of the parts that read their values from the current position through
+// new section readers, or nil when not all values implement io.ReaderAt and io.Seeker.
+func sectionParts(parts []Part) func() []Part {
+	type section struct {
+		at     io.ReaderAt
+		offset int64
+		size   int64
+	}
 
-		req.Header.Set("content-type", writer.FormDataContentType())
-		req.Body = pr
+	sections := make([]section, len(parts))
+	for i, p := range parts {
+		at, ok := p.Value.(io.ReaderAt)
+		seeker, isSeeker := p.Value.(io.Seeker)
+		if !ok || !isSeeker {
+			return nil
+		}
+
+		offset, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil
+		}
+		end, err := seeker.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil
+		}
+		if _, err = seeker.Seek(offset, io.SeekStart); err != nil {
+			return nil
+		}
+		sections[i] = section{at: at, offset: offset, size: end - offset}
+	}
+
+	return func() []Part {
+		copies := slices.Clone(parts)
+		for i, s := range sections {
+			copies[i].Value = io.NewSectionReader(s.at, s.offset, s.size)
+		}
+		return copies
 	}
 }
 
+func writeParts(pw *io.PipeWriter, writer *multipart.Writer, parts []Part) {
+	defer pw.Close()
+
+	for _, p := range parts {
+		partHeader := textproto.MIMEHeader{}
+		if p.Filename != "" {
+			partHeader.Set("content-disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, p.Key, p.Filename))
+		} else {
+			partHeader.Set("content-disposition", fmt.Sprintf(`form-data; name="%s";`, p.Key))
+		}
+		partHeader.Set("content-type", p.ContentType())
+
+		part, err := writer.CreatePart(partHeader)
+		if err != nil {
+			_ = pw.CloseWithError(err)
+			return
+		}
+
+		_, err = io.Copy(part, p.Value)
+		if err != nil {
+			_ = pw.CloseWithError(err)
+			return
+		}
+	}
+
+	_ = writer.Close()
+}
+
 func JsonBody(v any) RequestConfig {
 	return func(req *http.Request) {
 		req.Header.Set("content-type", "application/json")
@@ -87,6 +152,11 @@ func JsonBody(v any) RequestConfig {
 		if err != nil {
 			return
 		}
-		req.Body = io.NopCloser(bytes.NewBuffer(data))
+
+		req.ContentLength = int64(len(data))
+		req.Body = io.NopCloser(bytes.NewReader(data))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(data)), nil
+		}
 	}
 }
@@ -2,10 +2,8 @@ package graphdb
 
 import (
 	"encoding/json"
-	"errors"
-	"fmt"
-	"io"
 	"net/http"
+	"slices"
 )
 
 // CombinedResponseHandler creates a single response handler from multiple.
@@ -22,24 +20,27 @@ func CombinedResponseHandler(handlers ...ResponseHandler) ResponseHandler {
 	}
 }
 
+// ExpectStatusCode returns a handler failing with an APIError when the response status is not code.
 func ExpectStatusCode(code int) ResponseHandler {
-	return func(resp *http.Response) error {
-		if resp.StatusCode == code {
-			return nil
-		}
+	return ExpectStatusCodeFor("", code)
+}
 
-		return fmt.Errorf("expected status code %d but got %d", code, resp.StatusCode)
+// ExpectOneOfStatusCode returns a handler failing with an APIError when the response status is none of codes.
+func ExpectOneOfStatusCode(codes ...int) ResponseHandler {
+	return ExpectOneOfStatusCodeFor("", codes...)
+}
+
+// ExpectStatusCodeFor is ExpectStatusCode with the APIError naming op as the failed operation.
+func ExpectStatusCodeFor(op string, code int) ResponseHandler {
+	return func(resp *http.Response) error {
+		return ErrNotStatus(code, op, resp)
 	}
 }
 
-func ExpectOneOfStatusCode(codes ...int) ResponseHandler {
+// ExpectOneOfStatusCodeFor is ExpectOneOfStatusCode with the APIError naming op as the failed operation.
+func ExpectOneOfStatusCodeFor(op string, codes ...int) ResponseHandler {
 	return func(resp *http.Response) error {
-		for _, code := range codes {
-			if resp.StatusCode == code {
-				return nil
-			}
-		}
-		return fmt.Errorf("expected one of %v status codes but got %d", codes, resp.StatusCode)
+		return ErrNotOneOfStatus(op, resp, codes...)
 	}
 }
 
@@ -53,18 +54,18 @@ func UnmarshalJson(v any) ResponseHandler {
 	}
 }
 
-func ErrNotStatus(status int, message string, resp *http.Response) error {
+// ErrNotStatus returns an APIError for op when the response status is not the expected one.
+func ErrNotStatus(status int, op string, resp *http.Response) error {
 	if resp.StatusCode == status {
 		return nil
 	}
+	return NewAPIError(op, resp)
+}
 
-	all, _ := io.ReadAll(resp.Body)
-	if len(all) > 0 && message != "" {
-		return fmt.Errorf("%s: %s", message, string(all))
-	}
-
-	if message != "" {
-		return errors.New(message)
+// ErrNotOneOfStatus returns an APIError for op when the response status is none of the expected ones.
+func ErrNotOneOfStatus(op string, resp *http.Response, statuses ...int) error {
+	if slices.Contains(statuses, resp.StatusCode) {
+		return nil
 	}
-	return fmt.Errorf("unexpected status code: %d", status)
+	return NewAPIError(op, resp)
 }
@@ -0,0 +1,188 @@
+package graphdb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures automatic retries of failed requests. A request is retried when it fails with a transport
+// error or one of the Statuses, it is idempotent and its body can be replayed. The delay starts at InitialBackoff and
+// is multiplied by Multiplier after each attempt, up to MaxBackoff, and randomized by +/- Jitter. A Retry-After header
+// in the response takes precedence when it asks for a longer delay, unless it is longer than MaxBackoff, in which case
+// the response is returned without retrying.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one. Retries are disabled when it is below 2.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// Jitter is the fraction of the delay that is randomized, between 0 and 1.
+	Jitter   float64
+	Statuses []int
+	// Methods are the HTTP methods considered idempotent. Requests with other methods are only retried when marked
+	// with Idempotent.
+	Methods []string
+}
+
+// WithRetry enables retries of failed requests according to the policy. Unset fields get defaults: 200ms initial
+// backoff, 10s max backoff, multiplier 2, jitter 0.2, retry on 429, 502, 503 and 504, and GET, HEAD, OPTIONS, PUT and
+// DELETE as idempotent methods.
+func WithRetry(policy RetryPolicy) Option {
+	return func(client *Client) {
+		client.retry = policy.withDefaults()
+	}
+}
+
+// Idempotent marks a request as safe to retry regardless of its method, e.g. a SPARQL query sent with POST.
+func Idempotent() RequestConfig {
+	return idempotent(true)
+}
+
+// NonIdempotent marks a request as unsafe to retry regardless of its method.
+func NonIdempotent() RequestConfig {
+	return idempotent(false)
+}
+
+type idempotentKey struct{}
+
+func idempotent(v bool) RequestConfig {
+	return func(req *http.Request) {
+		*req = *req.WithContext(context.WithValue(req.Context(), idempotentKey{}, v))
+	}
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = 200 * time.Millisecond
+	}
+	if p.MaxBackoff < p.InitialBackoff {
+		p.MaxBackoff = max(10*time.Second, p.InitialBackoff)
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+	if p.Jitter <= 0 || p.Jitter > 1 {
+		p.Jitter = 0.2
+	}
+	if p.Statuses == nil {
+		p.Statuses = []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+	}
+	if p.Methods == nil {
+		p.Methods = []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete}
+	}
+	return p
+}
+
+// retryable reports whether the request may be sent again.
+func (p RetryPolicy) retryable(req *http.Request) bool {
+	if !replayable(req) {
+		return false
+	}
+
+	if v, ok := req.Context().Value(idempotentKey{}).(bool); ok {
+		return v
+	}
+	return readOnly(req) || slices.Contains(p.Methods, req.Method)
+}
+
+// backoff returns the randomized delay before the next attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := float64(p.InitialBackoff)
+	for i := 1; i < attempt && delay < float64(p.MaxBackoff); i++ {
+		delay *= p.Multiplier
+	}
+	delay = min(delay, float64(p.MaxBackoff))
+	return time.Duration(delay * (1 + p.Jitter*(2*rand.Float64()-1)))
+}
+
+// send executes the request, retrying it according to the retry policy of the client.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		logRequest(req, attempt)
+		resp, err := c.hc.Do(req)
+		if attempt >= c.retry.MaxAttempts || !c.retry.retryable(req) || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		var delay time.Duration
+		switch {
+		case err != nil:
+			delay = c.retry.backoff(attempt)
+		case slices.Contains(c.retry.Statuses, resp.StatusCode):
+			after := retryAfter(resp)
+			if after > c.retry.MaxBackoff {
+				return resp, nil
+			}
+			delay = max(c.retry.backoff(attempt), after)
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		default:
+			return resp, nil
+		}
+
+		next, replayErr := replay(req)
+		if replayErr != nil {
+			return nil, errors.Join(err, replayErr)
+		}
+
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+		req = next
+	}
+}
+
+// replayable reports whether the body of the request can be sent again.
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// replay returns a copy of the request with a fresh body.
+func replay(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.GetBody == nil {
+		return next, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	next.Body = body
+	return next, nil
+}
+
+// retryAfter returns the delay requested by the Retry-After header, in seconds or as a date.
+func retryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("retry-after")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0)
+	}
+	return 0
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
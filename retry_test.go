@@ -0,0 +1,177 @@
+package graphdb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_Retry(t *testing.T) {
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	bodies := map[string][]string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		key := r.Method + " " + r.URL.Path
+		attempts[key]++
+		bodies[key] = append(bodies[key], string(body))
+		n := attempts[key]
+		mu.Unlock()
+
+		if n < 3 {
+			w.Header().Set("retry-after", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		if r.URL.Path == PathRepositories {
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		_, _ = w.Write([]byte(`{"boolean":true}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := New(server.URL, WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+
+	config := RepositoryConfig{Id: "test", Title: "Test"}
+	if err := client.Repositories().Edit(ctx, "test", config); err != nil {
+		t.Fatalf("expected edit to succeed after retries: %v", err)
+	}
+
+	edits := bodies["PUT /rest/repositories/test"]
+	if len(edits) != 3 || edits[0] == "" || edits[0] != edits[2] {
+		t.Errorf("expected the json body to be replayed on each attempt, got %q", edits)
+	}
+
+	// POST is not idempotent, unless marked otherwise
+	err := client.Repositories().Create(ctx, JsonBody(config))
+	if err == nil || attempts["POST /rest/repositories"] != 1 {
+		t.Errorf("expected a single create attempt, got %d: %v", attempts["POST /rest/repositories"], err)
+	}
+
+	if ok, err := client.RDF4J().Ask(ctx, "test", "ASK {}"); err != nil || !ok {
+		t.Errorf("expected the query to be retried: %v", err)
+	}
+
+	err = client.Repositories().ImportUpload(ctx, "test", "data.ttl", strings.NewReader("<urn:a> <urn:b> <urn:c> ."), ImportSettings{},
+		Idempotent())
+	if err != nil {
+		t.Fatalf("expected upload to succeed after retries: %v", err)
+	}
+
+	uploads := bodies["POST /rest/repositories/test/import/upload/file"]
+	if len(uploads) != 3 || !strings.Contains(uploads[2], "<urn:a> <urn:b> <urn:c> .") || uploads[0] != uploads[2] {
+		t.Errorf("expected the multipart body to be replayed on each attempt, got %q", uploads)
+	}
+}
+
+func TestClient_RetryNotReplayable(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := New(server.URL, WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+
+	// a pipe cannot be rewound, so the request is sent once
+	pr, pw := io.Pipe()
+	go func() {
+		_, _ = pw.Write([]byte("<urn:a> <urn:b> <urn:c> ."))
+		_ = pw.Close()
+	}()
+
+	err := client.Repositories().ImportUpload(context.Background(), "test", "data.ttl", pr, ImportSettings{}, Idempotent())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway || attempts != 1 {
+		t.Errorf("expected a single attempt, got %d: %v", attempts, err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("retry-after", "2")
+	if d := retryAfter(resp); d != 2*time.Second {
+		t.Errorf("expected 2s, got %s", d)
+	}
+
+	resp.Header.Set("retry-after", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	if d := retryAfter(resp); d < 50*time.Second || d > time.Minute {
+		t.Errorf("expected about a minute, got %s", d)
+	}
+
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}.withDefaults()
+	if d := policy.backoff(10); d < 800*time.Millisecond || d > 1200*time.Millisecond {
+		t.Errorf("expected the backoff to be capped at about 1s, got %s", d)
+	}
+}
+
+func TestMultipartFormData_Replay(t *testing.T) {
+	value := strings.NewReader("skip:<urn:a> <urn:b> <urn:c> .")
+	_, _ = value.Seek(5, io.SeekStart)
+
+	req, _ := http.NewRequest(http.MethodPost, "http://localhost", nil)
+	MultipartFormData(Part{Key: "file", Filename: "data.ttl", Value: value})(req)
+	if req.GetBody == nil {
+		t.Fatal("expected a replayable body")
+	}
+
+	// the first attempt is still being read while the body is replayed
+	var wg sync.WaitGroup
+	bodies := make([]string, 3)
+	for i := range bodies {
+		body := req.Body
+		if i > 0 {
+			var err error
+			if body, err = req.GetBody(); err != nil {
+				t.Fatalf("failed to replay body: %v", err)
+			}
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, _ := io.ReadAll(body)
+			bodies[i] = string(data)
+		}()
+	}
+	wg.Wait()
+
+	if !strings.Contains(bodies[0], "\r\n<urn:a> <urn:b> <urn:c> .\r\n") || bodies[0] != bodies[1] || bodies[0] != bodies[2] {
+		t.Errorf("expected identical bodies from the original position, got %q", bodies)
+	}
+}
+
+func TestClient_RetryAfterTooLong(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("retry-after", "86400")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := New(server.URL, WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Second}))
+
+	start := time.Now()
+	_, err := client.Repositories().Infos(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable || attempts != 1 {
+		t.Errorf("expected the response to be returned without retrying, got %d attempts: %v", attempts, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected no wait for the retry-after delay, took %v", elapsed)
+	}
+}
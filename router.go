@@ -0,0 +1,325 @@
+package graphdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	NodeStateLeader   = "LEADER"
+	NodeStateFollower = "FOLLOWER"
+	SyncStatusInSync  = "IN_SYNC"
+)
+
+// DefaultTopologyRefresh is how often a cluster client discovers the topology of the cluster again.
+const DefaultTopologyRefresh = 30 * time.Second
+
+const (
+	// routeAttempts is the number of nodes a request is sent to before giving up, enough to outlast an election.
+	routeAttempts   = 5
+	electionBackoff = 500 * time.Millisecond
+)
+
+// ErrNoLeader is returned for writes to a cluster without a leader, e.g. during an election.
+var ErrNoLeader = errors.New("cluster has no leader")
+
+// NewClusterClient creates a client for a GraphDB cluster. The topology is discovered from the group status of the
+// nodes at urls, and is discovered again periodically and whenever a node fails. Writes and management calls are
+// sent to the leader, and reads are spread across the followers in sync with it. Requests that fail because the
+// leader changed, with 503 or a redirect, are sent to the new leader.
+func NewClusterClient(urls []string, opts ...Option) *Client {
+	r := &router{seeds: slices.Clone(urls), interval: DefaultTopologyRefresh}
+	seed := ""
+	if len(urls) > 0 {
+		seed = urls[0]
+	}
+
+	opts = append([]Option{func(client *Client) {
+		client.router = r
+		r.client = client
+	}}, opts...)
+
+	client := New(seed, opts...)
+	if u, err := url.Parse(client.url); err == nil {
+		r.base = strings.TrimSuffix(u.Path, "/")
+	}
+	return client
+}
+
+// WithTopologyRefresh sets how often a cluster client discovers the topology of the cluster.
+func WithTopologyRefresh(interval time.Duration) Option {
+	return func(client *Client) {
+		if client.router != nil {
+			client.router.interval = interval
+		}
+	}
+}
+
+// OnNode sends a request of a cluster client to the node with the given url instead of routing it. Other clients
+// ignore it.
+func OnNode(url string) RequestConfig {
+	return func(req *http.Request) {
+		*req = *req.WithContext(context.WithValue(req.Context(), nodeKey{}, url))
+	}
+}
+
+type nodeKey struct{}
+
+// Topology is the cluster topology known to a cluster client.
+type Topology struct {
+	Leader    string
+	Followers []string
+}
+
+// Topology returns the last discovered topology of the cluster. It is empty for clients not created with
+// NewClusterClient, and before the first request of a cluster client.
+func (c *Client) Topology() Topology {
+	if c.router == nil {
+		return Topology{}
+	}
+
+	c.router.mu.Lock()
+	defer c.router.mu.Unlock()
+	return Topology{Leader: c.router.leader, Followers: slices.Clone(c.router.followers)}
+}
+
+// route sends the request to the node chosen by the router of a cluster client.
+func (c *Client) route(req *http.Request) (*http.Response, error) {
+	if c.router == nil {
+		return c.guardedSend(req)
+	}
+	return c.router.Do(req)
+}
+
+type router struct {
+	client   *Client
+	seeds    []string
+	base     string
+	interval time.Duration
+
+	// discovering serializes topology discoveries
+	discovering sync.Mutex
+	next        atomic.Uint64
+
+	mu         sync.Mutex
+	leader     string
+	followers  []string
+	discovered time.Time
+}
+
+func (r *router) Do(req *http.Request) (*http.Response, error) {
+	path := strings.TrimPrefix(req.URL.Path, r.base)
+	rawPath := strings.TrimPrefix(req.URL.RawPath, r.base)
+
+	if node, ok := req.Context().Value(nodeKey{}).(string); ok {
+		if err := r.target(req, node, path, rawPath); err != nil {
+			return nil, err
+		}
+		return r.client.guardedSend(req)
+	}
+
+	write := mutating(req)
+	for attempt := 1; ; attempt++ {
+		node, err := r.pick(req.Context(), write)
+		if errors.Is(err, ErrNoLeader) && attempt < routeAttempts {
+			// wait for the election to end
+			if sleepErr := sleep(req.Context(), time.Duration(attempt)*electionBackoff); sleepErr != nil {
+				return nil, errors.Join(err, sleepErr)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if index, ok := req.Context().Value(indexKey{}).(int); ok && !write {
+			if err = r.client.cluster.WaitIndex(req.Context(), node, index); err != nil {
+				return nil, err
+			}
+		}
+		if err = r.target(req, node, path, rawPath); err != nil {
+			return nil, err
+		}
+
+		resp, err := r.client.guardedSend(req)
+		if errors.Is(err, ErrCircuitOpen) {
+			// fail fast, only reads can go to another node
+			if write || attempt >= routeAttempts {
+				return nil, err
+			}
+			continue
+		}
+		if !r.failover(req, resp, err) || attempt >= routeAttempts {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		r.forget(node)
+
+		if req, err = replay(req); err != nil {
+			return nil, err
+		}
+		if err = sleep(req.Context(), time.Duration(attempt-1)*electionBackoff); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// failover reports whether the request failed because of the node it was sent to, and may be sent to another one.
+// Requests that are not idempotent are only sent again when the node did not process them: it answered 503 or could
+// not be connected to.
+func (r *router) failover(req *http.Request, resp *http.Response, err error) bool {
+	if !replayable(req) || err != nil && req.Context().Err() != nil {
+		return false
+	}
+
+	var opErr *net.OpError
+	if err == nil && resp.StatusCode == http.StatusServiceUnavailable || errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	if !r.client.retry.withDefaults().retryable(req) {
+		return false
+	}
+	return err != nil || resp.StatusCode >= http.StatusMultipleChoices && resp.StatusCode < http.StatusBadRequest
+}
+
+// target points the request at the node.
+func (r *router) target(req *http.Request, node, path, rawPath string) error {
+	u, err := url.Parse(node)
+	if err != nil {
+		return fmt.Errorf("cluster_route: %w", err)
+	}
+
+	base := strings.TrimSuffix(u.Path, "/")
+	target := *req.URL
+	target.Scheme, target.Host, target.Path = u.Scheme, u.Host, base+path
+	if rawPath != "" {
+		target.RawPath = base + rawPath
+	}
+	req.URL, req.Host = &target, u.Host
+	return nil
+}
+
+// pick returns the node to send the request to, discovering the topology when it is unknown or outdated.
+func (r *router) pick(ctx context.Context, write bool) (string, error) {
+	r.mu.Lock()
+	stale := time.Since(r.discovered) > r.interval || (write && r.leader == "")
+	r.mu.Unlock()
+
+	if stale {
+		if err := r.discover(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	r.mu.Lock()
+	leader, followers := r.leader, slices.Clone(r.followers)
+	r.mu.Unlock()
+
+	// reads skip the followers with open circuits, checked without mu as it may call OnStateChange
+	if write {
+		followers = nil
+	}
+	followers = slices.DeleteFunc(followers, func(node string) bool {
+		return !r.client.breakers.available(endpointNode(node))
+	})
+
+	switch {
+	case len(followers) > 0:
+		return followers[r.next.Add(1)%uint64(len(followers))], nil
+	case leader != "":
+		return leader, nil
+	default:
+		return "", fmt.Errorf("cluster_route: %w", ErrNoLeader)
+	}
+}
+
+// forget drops the node from the topology until it is discovered again.
+func (r *router) forget(node string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.leader == node {
+		r.leader = ""
+	}
+	r.followers = slices.DeleteFunc(r.followers, func(n string) bool { return n == node })
+	r.discovered = time.Time{}
+}
+
+// discover reads the group status from the known nodes until one of them responds.
+func (r *router) discover(ctx context.Context) error {
+	start := time.Now()
+	r.discovering.Lock()
+	defer r.discovering.Unlock()
+
+	r.mu.Lock()
+	known := slices.Concat([]string{r.leader}, r.followers, r.seeds)
+	done := r.discovered.After(start)
+	r.mu.Unlock()
+
+	// another request discovered the topology while this one waited
+	if done {
+		return nil
+	}
+
+	var nodes []string
+	for _, node := range known {
+		if node != "" && !slices.Contains(nodes, node) {
+			nodes = append(nodes, node)
+		}
+	}
+	if len(nodes) == 0 {
+		return errors.New("cluster_discovery: no nodes")
+	}
+
+	var errs []error
+	for _, node := range nodes {
+		status, err := r.client.Cluster().Status(ctx, OnNode(node))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		leader, followers := topology(status)
+		r.mu.Lock()
+		r.leader, r.followers, r.discovered = leader, followers, time.Now()
+		r.mu.Unlock()
+		return nil
+	}
+	return fmt.Errorf("cluster_discovery: %w", errors.Join(errs...))
+}
+
+// topology returns the endpoint of the leader and the endpoints of the followers in sync with it.
+func topology(status []NodeStatus) (string, []string) {
+	var leader NodeStatus
+	for _, node := range status {
+		if node.NodeState == NodeStateLeader {
+			leader = node
+		}
+	}
+
+	var followers []string
+	for _, node := range status {
+		if node.NodeState != NodeStateFollower || node.Endpoint == "" {
+			continue
+		}
+		if leader.SyncStatus != nil && leader.SyncStatus[node.Address] != SyncStatusInSync {
+			continue
+		}
+		followers = append(followers, node.Endpoint)
+	}
+	return leader.Endpoint, followers
+}
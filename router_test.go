@@ -0,0 +1,215 @@
+package graphdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeCluster serves the group status and repository endpoints from a number of nodes, of which one is the leader.
+type fakeCluster struct {
+	mu      sync.Mutex
+	nodes   []*httptest.Server
+	leader  int
+	inSync  []bool
+	index   []int
+	hits    map[string][]int
+	writeTo []int
+	// failWrites makes the leader fail writes with 500
+	failWrites bool
+}
+
+func newFakeCluster(t *testing.T, n int) *fakeCluster {
+	f := &fakeCluster{leader: 0, hits: map[string][]int{}}
+	for i := range n {
+		f.inSync = append(f.inSync, true)
+		f.index = append(f.index, 0)
+		f.nodes = append(f.nodes, httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			f.serve(i, w, r)
+		})))
+	}
+	t.Cleanup(func() {
+		for _, node := range f.nodes {
+			node.Close()
+		}
+	})
+	return f
+}
+
+func (f *fakeCluster) serve(i int, w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.URL.Path == PathClusterGroupStatus:
+		f.hits[r.URL.Path] = append(f.hits[r.URL.Path], i)
+		var status []NodeStatus
+		syncStatus := map[string]string{}
+		for j, node := range f.nodes {
+			state := NodeStateFollower
+			if j == f.leader {
+				state = NodeStateLeader
+			}
+			address := fmt.Sprintf("node%d:7300", j)
+			status = append(status, NodeStatus{Address: address, NodeState: state, Endpoint: node.URL})
+			syncStatus[address] = "OUT_OF_SYNC"
+			if f.inSync[j] {
+				syncStatus[address] = SyncStatusInSync
+			}
+		}
+		if f.leader >= 0 {
+			status[f.leader].SyncStatus = syncStatus
+		}
+		_ = json.NewEncoder(w).Encode(status)
+	case r.URL.Path == PathClusterNodeStatus:
+		// followers catch up with the leader one entry per status request
+		if i != f.leader && f.index[i] < f.index[f.leader] {
+			f.index[i]++
+		}
+		_ = json.NewEncoder(w).Encode(NodeStatus{LastLogIndex: f.index[i]})
+	case r.Method == http.MethodGet:
+		f.hits[r.URL.Path] = append(f.hits[r.URL.Path], i)
+		if f.leader >= 0 && f.index[i] < f.index[f.leader] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	case i != f.leader:
+		w.WriteHeader(http.StatusServiceUnavailable)
+	case f.failWrites:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		f.writeTo = append(f.writeTo, i)
+		f.index[i] += 3
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+func TestNewClusterClient_Routing(t *testing.T) {
+	cluster := newFakeCluster(t, 3)
+	cluster.inSync[2] = false
+
+	client := NewClusterClient([]string{cluster.nodes[2].URL, cluster.nodes[1].URL})
+	ctx := context.Background()
+
+	for range 3 {
+		if _, err := client.Repositories().Infos(ctx); err != nil {
+			t.Fatalf("failed to list repositories: %v", err)
+		}
+	}
+	if reads := cluster.hits[PathRepositories]; len(reads) != 3 || reads[0] != 1 || reads[1] != 1 || reads[2] != 1 {
+		t.Errorf("expected reads on the only follower in sync, got nodes %v", reads)
+	}
+
+	if err := client.Repositories().Create(ctx, JsonBody(RepositoryConfig{Id: "test"})); err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+
+	cluster.mu.Lock()
+	cluster.leader, cluster.inSync[2] = 2, true
+	cluster.mu.Unlock()
+
+	if err := client.Repositories().Create(ctx, JsonBody(RepositoryConfig{Id: "test"})); err != nil {
+		t.Fatalf("failed to create repository after the leader changed: %v", err)
+	}
+	if len(cluster.writeTo) != 2 || cluster.writeTo[0] != 0 || cluster.writeTo[1] != 2 {
+		t.Errorf("expected writes on the leaders, got nodes %v", cluster.writeTo)
+	}
+
+	topology := client.Topology()
+	if topology.Leader != cluster.nodes[2].URL || len(topology.Followers) != 2 {
+		t.Errorf("unexpected topology: %+v", topology)
+	}
+
+	cluster.hits = map[string][]int{}
+	if _, err := client.Repositories().Infos(ctx, OnNode(cluster.nodes[2].URL)); err != nil {
+		t.Fatalf("failed to list repositories: %v", err)
+	}
+	if reads := cluster.hits[PathRepositories]; len(reads) != 1 || reads[0] != 2 {
+		t.Errorf("expected read on the pinned node, got nodes %v", reads)
+	}
+}
+
+func TestNewClusterClient_NoLeader(t *testing.T) {
+	cluster := newFakeCluster(t, 2)
+	cluster.leader = -1
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	client := NewClusterClient([]string{cluster.nodes[0].URL})
+	err := client.Repositories().Create(ctx, JsonBody(RepositoryConfig{Id: "test"}))
+	if !errors.Is(err, ErrNoLeader) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected ErrNoLeader after waiting for the election, got %v", err)
+	}
+	if _, err = client.Repositories().Infos(context.Background()); err != nil {
+		t.Errorf("expected reads on the followers during the election, got %v", err)
+	}
+}
+
+func TestClusterClient_ReadYourWrites(t *testing.T) {
+	cluster := newFakeCluster(t, 2)
+	client := NewClusterClient([]string{cluster.nodes[0].URL}, WithPolling(Polling{Interval: time.Millisecond}))
+	ctx := context.Background()
+
+	if err := client.Repositories().Create(ctx, JsonBody(RepositoryConfig{Id: "test"})); err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+
+	index, err := client.Cluster().LeaderIndex(ctx)
+	if err != nil {
+		t.Fatalf("failed to get leader index: %v", err)
+	}
+	if index != 3 {
+		t.Errorf("expected leader index 3, got %d", index)
+	}
+
+	if _, err = client.Repositories().Infos(ctx, AfterIndex(index)); err != nil {
+		t.Fatalf("failed to read own write: %v", err)
+	}
+	if reads := cluster.hits[PathRepositories]; len(reads) != 1 || reads[0] != 1 || cluster.index[1] != 3 {
+		t.Errorf("expected read on the follower once it caught up, got nodes %v, index %d", reads, cluster.index[1])
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err = client.Cluster().WaitIndex(waitCtx, cluster.nodes[1].URL, 10); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected to time out waiting for an unreached index, got %v", err)
+	}
+}
+
+func TestNewClusterClient_NoFailoverOfWrites(t *testing.T) {
+	var posts atomic.Int32
+	var leader *httptest.Server
+	leader = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == PathClusterGroupStatus {
+			_ = json.NewEncoder(w).Encode([]NodeStatus{{NodeState: NodeStateLeader, Endpoint: leader.URL}})
+			return
+		}
+
+		// the write reaches the server, which drops the connection before responding
+		posts.Add(1)
+		_, _ = io.ReadAll(r.Body)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			_ = conn.Close()
+		}
+	}))
+	defer leader.Close()
+
+	client := NewClusterClient([]string{leader.URL})
+	if err := client.Repositories().Create(context.Background(), JsonBody(RepositoryConfig{Id: "test"})); err == nil {
+		t.Fatal("expected create to fail")
+	}
+	if posts.Load() != 1 {
+		t.Errorf("expected the write to be sent once, got %d", posts.Load())
+	}
+}
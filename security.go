@@ -3,12 +3,10 @@ package graphdb
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
-
-	"github.com/pkg/errors"
 )
 
 const (
@@ -65,27 +63,29 @@ type SecurityClient struct {
 func (s *SecurityClient) Enabled(ctx context.Context, config ...RequestConfig) (bool, error) {
 	var enabled bool
 	return enabled, s.client.get(ctx, PathSecurity, func(resp *http.Response) error {
-		return json.NewDecoder(resp.Body).Decode(&enabled)
+		if err := ErrNotStatus(http.StatusOK, "security", resp); err != nil {
+			return err
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(&enabled); err != nil {
+			return fmt.Errorf("security: %w", err)
+		}
+		return nil
 	}, config...)
 }
 
 func (s *SecurityClient) SetEnabled(ctx context.Context, enabled bool, config ...RequestConfig) error {
 	config = append(config, JsonBody(enabled))
 	return s.client.post(ctx, PathSecurity, nil, func(resp *http.Response) error {
-		if resp.StatusCode != http.StatusOK {
-			all, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("security: toggling to %t failed, %s", enabled, string(all))
-		}
-		return nil
+		return ErrNotStatus(http.StatusOK, "security", resp)
 	}, config...)
 }
 
 func (s *SecurityClient) FreeAccess(ctx context.Context, config ...RequestConfig) (FreeAccess, error) {
 	var free FreeAccess
 	return free, s.client.get(ctx, PathSecurityFreeAccess, func(resp *http.Response) error {
-		if resp.StatusCode != http.StatusOK {
-			all, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("security/free-access: %s", string(all))
+		if err := ErrNotStatus(http.StatusOK, "free_access", resp); err != nil {
+			return err
 		}
 		return json.NewDecoder(resp.Body).Decode(&free)
 	}, config...)
@@ -94,20 +94,15 @@ func (s *SecurityClient) FreeAccess(ctx context.Context, config ...RequestConfig
 func (s *SecurityClient) ConfigureFreeAccess(ctx context.Context, access FreeAccess, config ...RequestConfig) error {
 	config = append(config, JsonBody(access))
 	return s.client.post(ctx, PathSecurityFreeAccess, nil, func(resp *http.Response) error {
-		if resp.StatusCode != http.StatusOK {
-			all, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("security/free-access: %s", string(all))
-		}
-		return nil
+		return ErrNotStatus(http.StatusOK, "free_access", resp)
 	}, config...)
 }
 
 func (s *SecurityClient) Users(ctx context.Context, config ...RequestConfig) ([]User, error) {
 	var users []User
 	return users, s.client.get(ctx, PathSecurityUsers, func(resp *http.Response) error {
-		if resp.StatusCode != http.StatusOK {
-			all, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("users: %s", string(all))
+		if err := ErrNotStatus(http.StatusOK, "users", resp); err != nil {
+			return err
 		}
 		return json.NewDecoder(resp.Body).Decode(&users)
 	}, config...)
@@ -116,9 +111,8 @@ func (s *SecurityClient) Users(ctx context.Context, config ...RequestConfig) ([]
 func (s *SecurityClient) User(ctx context.Context, username string, config ...RequestConfig) (User, error) {
 	var user User
 	return user, s.client.get(ctx, fmt.Sprintf(PathSecurityUser, username), func(resp *http.Response) error {
-		if resp.StatusCode != http.StatusOK {
-			all, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("users: %s", string(all))
+		if err := ErrNotStatus(http.StatusOK, "users", resp); err != nil {
+			return err
 		}
 		return json.NewDecoder(resp.Body).Decode(&user)
 	}, config...)
@@ -128,11 +122,7 @@ func (s *SecurityClient) CreateUser(ctx context.Context, user User, config ...Re
 	config = append(config, JsonBody(user))
 
 	return s.client.post(ctx, fmt.Sprintf(PathSecurityUser, user.Username), nil, func(resp *http.Response) error {
-		if resp.StatusCode != http.StatusCreated {
-			all, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("user: %s", string(all))
-		}
-		return nil
+		return ErrNotStatus(http.StatusCreated, "users", resp)
 	}, config...)
 }
 
@@ -140,11 +130,7 @@ func (s *SecurityClient) UpdateUser(ctx context.Context, user User, config ...Re
 	config = append(config, JsonBody(user))
 
 	return s.client.put(ctx, fmt.Sprintf(PathSecurityUser, user.Username), nil, func(resp *http.Response) error {
-		if resp.StatusCode != http.StatusOK {
-			all, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("user: %s", string(all))
-		}
-		return nil
+		return ErrNotStatus(http.StatusOK, "users", resp)
 	}, config...)
 }
 
@@ -152,21 +138,13 @@ func (s *SecurityClient) UpdateUserSettings(ctx context.Context, user User, conf
 	config = append(config, JsonBody(user.AppSettings))
 
 	return s.client.patch(ctx, fmt.Sprintf(PathSecurityUser, user.Username), nil, func(resp *http.Response) error {
-		if resp.StatusCode != http.StatusOK {
-			all, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("users: %s", string(all))
-		}
-		return nil
+		return ErrNotStatus(http.StatusOK, "users", resp)
 	}, config...)
 }
 
 func (s *SecurityClient) DeleteUser(ctx context.Context, username string, config ...RequestConfig) error {
 	return s.client.delete(ctx, fmt.Sprintf(PathSecurityUser, username), nil, func(resp *http.Response) error {
-		if resp.StatusCode != http.StatusNoContent {
-			all, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("users: %s", string(all))
-		}
-		return nil
+		return ErrNotStatus(http.StatusNoContent, "users", resp)
 	}, config...)
 }
 
@@ -174,86 +152,76 @@ func (s *SecurityClient) Login(ctx context.Context, username, password string, c
 	var token string
 	var details UserDetails
 
+	// logging in does not change the server, it must pass DryRun and stay out of Audit
+	config = append([]RequestConfig{ReadOnly()}, config...)
 	config = append(config, JsonBody(map[string]string{
 		"username": username,
 		"password": password,
 	}))
 	return token, details, s.client.post(ctx, PathLogin, nil, func(resp *http.Response) error {
-		if resp.StatusCode != http.StatusOK {
-			all, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("users_login: %s", string(all))
+		if err := ErrNotStatus(http.StatusOK, "users_login", resp); err != nil {
+			return err
 		}
 
 		parts := strings.SplitN(resp.Header.Get("authorization"), " ", 2)
-		if len(parts) == 2 || parts[0] != "GDB" {
-			token = parts[1]
+		if len(parts) != 2 || parts[0] != "GDB" || parts[1] == "" {
+			return errors.New("users_login: no GDB token in response")
 		}
+		token = parts[1]
 		return json.NewDecoder(resp.Body).Decode(&details)
 	}, config...)
 }
 
-func (s *SecurityClient) GetCustomRoles(ctx context.Context, config ...RequestConfig) (map[string]string, error) {
-	var roles map[string]string
+func (s *SecurityClient) GetCustomRoles(ctx context.Context, config ...RequestConfig) (map[string][]string, error) {
+	var roles map[string][]string
 	return roles, s.client.get(ctx, PathCustomRoles, func(resp *http.Response) error {
-		if resp.StatusCode != http.StatusOK {
-			all, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("custom_roles: %s", string(all))
+		if err := ErrNotStatus(http.StatusOK, "custom_roles", resp); err != nil {
+			return err
 		}
-		return errors.Wrap(json.NewDecoder(resp.Body).Decode(&roles), "custom_roles")
+		if err := json.NewDecoder(resp.Body).Decode(&roles); err != nil {
+			return fmt.Errorf("custom_roles: %w", err)
+		}
+		return nil
 	}, config...)
 }
 
 func (s *SecurityClient) ReplaceCustomRoles(ctx context.Context, roles map[string][]string, config ...RequestConfig) error {
 	config = append(config, JsonBody(roles))
 	return s.client.put(ctx, PathCustomRoles, nil, func(resp *http.Response) error {
-		if resp.StatusCode != http.StatusOK {
-			all, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("custom_roles: %s", string(all))
-		}
-		return nil
+		return ErrNotStatus(http.StatusOK, "custom_roles", resp)
 	}, config...)
 }
 
 func (s *SecurityClient) GetCustomRoleUsers(ctx context.Context, role string, config ...RequestConfig) ([]string, error) {
 	var users []string
 	return users, s.client.get(ctx, fmt.Sprintf(PathCustomRole, role), func(resp *http.Response) error {
-		all, _ := io.ReadAll(resp.Body)
-		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("custom_roles: %s", string(all))
+		if err := ErrNotStatus(http.StatusOK, "custom_roles", resp); err != nil {
+			return err
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&users); err != nil {
+			return fmt.Errorf("custom_roles: %w", err)
 		}
-		return errors.Wrap(json.NewDecoder(resp.Body).Decode(&users), "custom_roles")
+		return nil
 	}, config...)
 }
 
 func (s *SecurityClient) ReplaceCustomRoleUsers(ctx context.Context, role string, users []string, config ...RequestConfig) error {
 	config = append(config, JsonBody(users))
 	return s.client.put(ctx, fmt.Sprintf(PathCustomRole, role), nil, func(resp *http.Response) error {
-		all, _ := io.ReadAll(resp.Body)
-		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("custom_roles: %s", string(all))
-		}
-		return nil
+		return ErrNotStatus(http.StatusOK, "custom_roles", resp)
 	}, config...)
 }
 
 func (s *SecurityClient) AddCustomRoleUsers(ctx context.Context, role string, users []string, config ...RequestConfig) error {
 	config = append(config, JsonBody(users))
 	return s.client.post(ctx, fmt.Sprintf(PathCustomRole, role), nil, func(resp *http.Response) error {
-		all, _ := io.ReadAll(resp.Body)
-		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("custom_roles: %s", string(all))
-		}
-		return nil
+		return ErrNotStatus(http.StatusOK, "custom_roles", resp)
 	}, config...)
 }
 
 func (s *SecurityClient) RemoveCustomRoleUsers(ctx context.Context, role string, users []string, config ...RequestConfig) error {
 	config = append(config, JsonBody(users))
 	return s.client.delete(ctx, fmt.Sprintf(PathCustomRole, role), nil, func(resp *http.Response) error {
-		all, _ := io.ReadAll(resp.Body)
-		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("custom_roles: %s", string(all))
-		}
-		return nil
+		return ErrNotStatus(http.StatusOK, "custom_roles", resp)
 	}, config...)
 }
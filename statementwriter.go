@@ -0,0 +1,311 @@
+package graphdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yaskoo/go-graphdb/rdf"
+)
+
+var ErrWriterClosed = errors.New("statement writer: closed")
+
+type StatementWriterOptions struct {
+	// BatchSize is the maximum number of statements sent in a single request. Defaults to 10000.
+	BatchSize int
+	// BatchBytes is the maximum size of the serialized batch in bytes. Defaults to 4MB.
+	BatchBytes int
+	// FlushInterval is the maximum time a statement waits in a partial batch before being sent. Defaults to 1s.
+	FlushInterval time.Duration
+	// Format is the serialization format used for uploads, rdf.FormatNQuads or rdf.FormatBinary. Defaults to N-Quads.
+	Format rdf.Format
+	// Transactional sends each batch as an add in a separate RDF4J transaction instead of a plain statements POST.
+	Transactional bool
+	// Concurrency is the number of batches uploaded in parallel. Defaults to 1.
+	Concurrency int
+	// MaxPending is the number of full batches that can wait for upload before Write blocks. Defaults to Concurrency.
+	MaxPending int
+	// RequestConfig is applied to every upload request.
+	RequestConfig []RequestConfig
+}
+
+func (o *StatementWriterOptions) defaults() {
+	if o.BatchSize <= 0 {
+		o.BatchSize = 10000
+	}
+	if o.BatchBytes <= 0 {
+		o.BatchBytes = 4 << 20
+	}
+	if o.FlushInterval <= 0 {
+		o.FlushInterval = time.Second
+	}
+	if o.Format == "" {
+		o.Format = rdf.FormatNQuads
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 1
+	}
+	if o.MaxPending <= 0 {
+		o.MaxPending = o.Concurrency
+	}
+}
+
+// StatementWriterStats summarizes the work done by a StatementWriter.
+type StatementWriterStats struct {
+	Statements       int64
+	Bytes            int64
+	Batches          int64
+	FailedStatements int64
+	FailedBatches    int64
+	Errors           []error
+}
+
+// StatementWriter batches statements written from many goroutines and uploads them to a repository.
+// Batches are sent when they reach the configured size, when the flush interval passes or on Flush and Close.
+// When uploads fall behind, Write blocks until there is room for more batches.
+type StatementWriter struct {
+	client *Client
+	repo   string
+	graph  rdf.Term
+	opts   StatementWriterOptions
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	batch   *statementBatch
+	closed  bool
+	batches chan *statementBatch
+	workers sync.WaitGroup
+	flusher sync.WaitGroup
+
+	statsMu sync.Mutex
+	stats   StatementWriterStats
+}
+
+type statementBatch struct {
+	buf     bytes.Buffer
+	enc     rdf.Encoder
+	count   int
+	created time.Time
+}
+
+// NewStatementWriter creates a writer that uploads statements to repo. Statements without a context are written to
+// graph, unless graph is empty, in which case they go to the default graph.
+// The context controls the lifetime of the background uploads, cancelling it aborts pending batches.
+func (r *RDF4J) NewStatementWriter(ctx context.Context, repo string, graph string, opts StatementWriterOptions) (*StatementWriter, error) {
+	opts.defaults()
+	if opts.Format != rdf.FormatNQuads && opts.Format != rdf.FormatBinary {
+		return nil, fmt.Errorf("statement writer: unsupported format %s", opts.Format)
+	}
+	// the upload workers share the configs, appending to them must not write to a shared backing array
+	opts.RequestConfig = opts.RequestConfig[:len(opts.RequestConfig):len(opts.RequestConfig)]
+
+	ctx, cancel := context.WithCancel(ctx)
+	w := &StatementWriter{
+		client:  r.client,
+		repo:    repo,
+		opts:    opts,
+		ctx:     ctx,
+		cancel:  cancel,
+		batches: make(chan *statementBatch, opts.MaxPending),
+	}
+	if graph != "" {
+		w.graph = rdf.IRI(graph)
+	}
+
+	for i := 0; i < opts.Concurrency; i++ {
+		w.workers.Add(1)
+		go w.upload()
+	}
+
+	w.flusher.Add(1)
+	go w.flushPeriodically()
+	return w, nil
+}
+
+// Write adds statements to the current batch. It is safe for concurrent use.
+func (w *StatementWriter) Write(statements ...rdf.Statement) error {
+	for _, s := range statements {
+		if s.Context == nil {
+			s.Context = w.graph
+		}
+
+		if err := w.write(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *StatementWriter) write(s rdf.Statement) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return ErrWriterClosed
+	}
+
+	if w.batch == nil {
+		w.batch = w.newBatch()
+	}
+
+	if err := w.batch.enc.Encode(s); err != nil {
+		return fmt.Errorf("statement writer: %w", err)
+	}
+	// the encoder buffers its output, the size of the batch is only known once it is flushed
+	if err := w.batch.enc.Flush(); err != nil {
+		return fmt.Errorf("statement writer: %w", err)
+	}
+	w.batch.count++
+
+	if w.batch.count >= w.opts.BatchSize || w.batch.buf.Len() >= w.opts.BatchBytes {
+		return w.enqueue()
+	}
+	return nil
+}
+
+// Flush sends the current partial batch, without waiting for it to be uploaded.
+func (w *StatementWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return ErrWriterClosed
+	}
+	return w.enqueue()
+}
+
+// Close flushes the remaining statements, waits for all uploads to finish and returns the totals.
+// The returned error joins all upload errors.
+func (w *StatementWriter) Close() (StatementWriterStats, error) {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return w.Stats(), ErrWriterClosed
+	}
+
+	err := w.enqueue()
+	w.closed = true
+	close(w.batches)
+	w.mu.Unlock()
+
+	w.workers.Wait()
+	w.cancel()
+	w.flusher.Wait()
+
+	stats := w.Stats()
+	if err != nil {
+		stats.Errors = append(stats.Errors, err)
+	}
+	return stats, errors.Join(stats.Errors...)
+}
+
+// Stats returns a snapshot of the writer totals.
+func (w *StatementWriter) Stats() StatementWriterStats {
+	w.statsMu.Lock()
+	defer w.statsMu.Unlock()
+
+	stats := w.stats
+	stats.Errors = append([]error(nil), w.stats.Errors...)
+	return stats
+}
+
+func (w *StatementWriter) newBatch() *statementBatch {
+	b := &statementBatch{created: time.Now()}
+	b.enc, _ = rdf.NewEncoder(&b.buf, w.opts.Format)
+	return b
+}
+
+// enqueue hands the current batch over to the upload workers, blocking while the queue is full.
+// Must be called with w.mu held, which also blocks concurrent writers and provides the backpressure.
+func (w *StatementWriter) enqueue() error {
+	b := w.batch
+	if b == nil || b.count == 0 {
+		return nil
+	}
+	w.batch = nil
+
+	if err := b.enc.Close(); err != nil {
+		return fmt.Errorf("statement writer: %w", err)
+	}
+
+	select {
+	case w.batches <- b:
+		return nil
+	case <-w.ctx.Done():
+		w.record(b, w.ctx.Err())
+		return w.ctx.Err()
+	}
+}
+
+func (w *StatementWriter) flushPeriodically() {
+	defer w.flusher.Done()
+
+	ticker := time.NewTicker(w.opts.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			if !w.closed && w.batch != nil && time.Since(w.batch.created) >= w.opts.FlushInterval {
+				_ = w.enqueue()
+			}
+			w.mu.Unlock()
+		}
+	}
+}
+
+func (w *StatementWriter) upload() {
+	defer w.workers.Done()
+
+	for b := range w.batches {
+		if err := w.ctx.Err(); err != nil {
+			w.record(b, err)
+			continue
+		}
+		w.record(b, w.send(b))
+	}
+}
+
+func (w *StatementWriter) send(b *statementBatch) error {
+	body := bytes.NewReader(b.buf.Bytes())
+	if !w.opts.Transactional {
+		return w.client.rdf4j.AddStatements(w.ctx, w.repo, w.opts.Format, body, w.opts.RequestConfig...)
+	}
+
+	tx, err := w.client.rdf4j.Begin(w.ctx, w.repo, w.opts.RequestConfig...)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Add(w.ctx, w.opts.Format, body, w.opts.RequestConfig...); err == nil {
+		err = tx.Commit(w.ctx, w.opts.RequestConfig...)
+	}
+	if err != nil {
+		return errors.Join(err, tx.Rollback(context.WithoutCancel(w.ctx), w.opts.RequestConfig...))
+	}
+	return nil
+}
+
+func (w *StatementWriter) record(b *statementBatch, err error) {
+	w.statsMu.Lock()
+	defer w.statsMu.Unlock()
+
+	if err != nil {
+		w.stats.FailedBatches++
+		w.stats.FailedStatements += int64(b.count)
+		w.stats.Errors = append(w.stats.Errors, fmt.Errorf("statement writer: batch of %d statements: %w", b.count, err))
+		return
+	}
+
+	w.stats.Batches++
+	w.stats.Statements += int64(b.count)
+	w.stats.Bytes += int64(b.buf.Len())
+}
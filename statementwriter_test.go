@@ -0,0 +1,241 @@
+package graphdb
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yaskoo/go-graphdb/rdf"
+)
+
+func TestStatementWriter_Batching(t *testing.T) {
+	var requests, lines atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/test/statements" || r.Header.Get("content-type") != "application/n-quads" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			if !strings.HasSuffix(scanner.Text(), "<http://example.org/graph> .") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			lines.Add(1)
+		}
+		requests.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+	writer, err := client.RDF4J().NewStatementWriter(context.Background(), "test", "http://example.org/graph", StatementWriterOptions{
+		BatchSize:   10,
+		Concurrency: 4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 5; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 21; i++ {
+				err := writer.Write(rdf.Statement{
+					Subject:   rdf.IRI(fmt.Sprintf("http://example.org/s/%d/%d", g, i)),
+					Predicate: rdf.IRI("http://example.org/p"),
+					Object:    rdf.NewLiteral("value"),
+				})
+				if err != nil {
+					t.Errorf("write failed: %v", err)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	stats, err := writer.Close()
+	if err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	if stats.Statements != 105 || lines.Load() != 105 {
+		t.Errorf("expected 105 statements, got %d written and %d received", stats.Statements, lines.Load())
+	}
+
+	if stats.Batches != 11 || requests.Load() != 11 {
+		t.Errorf("expected 11 batches, got %d sent and %d received", stats.Batches, requests.Load())
+	}
+
+	if err = writer.Write(rdf.Statement{}); err != ErrWriterClosed {
+		t.Errorf("expected closed writer error, got %v", err)
+	}
+}
+
+func TestStatementWriter_FlushIntervalAndErrors(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("malformed"))
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+	writer, err := client.RDF4J().NewStatementWriter(context.Background(), "test", "", StatementWriterOptions{
+		FlushInterval: 10 * time.Millisecond,
+		Format:        rdf.FormatBinary,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := rdf.Statement{Subject: rdf.BlankNode("b0"), Predicate: rdf.IRI("http://example.org/p"), Object: rdf.IRI("http://example.org/o")}
+	if err = writer.Write(s); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for writer.Stats().Batches == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if writer.Stats().Batches != 1 {
+		t.Fatal("expected the partial batch to be flushed by the timer")
+	}
+
+	if err = writer.Write(s, s); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := writer.Close()
+	if err == nil || !strings.Contains(err.Error(), "malformed") {
+		t.Errorf("expected upload error, got %v", err)
+	}
+
+	if stats.FailedBatches != 1 || stats.FailedStatements != 2 || stats.Statements != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestStatementWriter_BatchBytes(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+	writer, err := client.RDF4J().NewStatementWriter(context.Background(), "test", "", StatementWriterOptions{BatchBytes: 200})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// each statement is about 60 bytes, four of them fill a batch
+	for i := 0; i < 9; i++ {
+		if err = writer.Write(rdf.Statement{Subject: rdf.IRI(fmt.Sprintf("http://example.org/s/%d", i)), Predicate: rdf.IRI("http://example.org/p"), Object: rdf.NewLiteral("value")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := writer.Close()
+	if err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	if stats.Batches != 3 || requests.Load() != 3 {
+		t.Errorf("expected 3 batches, got %d sent and %d received", stats.Batches, requests.Load())
+	}
+}
+
+func TestStatementWriter_TransactionalCommitFailure(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Query().Get("action"))
+		mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost:
+			w.Header().Set("location", "/repositories/test/transactions/tx-1")
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPut && r.URL.Query().Get("action") == TransactionCommit:
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("commit failed"))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+	writer, err := client.RDF4J().NewStatementWriter(context.Background(), "test", "", StatementWriterOptions{Transactional: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = writer.Write(rdf.Statement{Subject: rdf.IRI("http://example.org/s"), Predicate: rdf.IRI("http://example.org/p"), Object: rdf.NewLiteral("value")}); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := writer.Close()
+	if err == nil || !strings.Contains(err.Error(), "commit failed") || stats.FailedBatches != 1 {
+		t.Errorf("expected the commit to fail, got %+v: %v", stats, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	expected := []string{"POST ", "PUT " + TransactionAdd, "PUT " + TransactionCommit, "DELETE "}
+	if strings.Join(requests, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected requests %q, got %q", expected, requests)
+	}
+}
+
+func TestStatementWriter_SharedRequestConfig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("content-type") != "application/n-quads" || r.Header.Get("x-job") != "load" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// spare capacity lets appends of the workers share the backing array
+	conf := make([]RequestConfig, 0, 8)
+	conf = append(conf, Header("x-job", "load"))
+
+	client := New(server.URL)
+	writer, err := client.RDF4J().NewStatementWriter(context.Background(), "test", "", StatementWriterOptions{
+		BatchSize:     1,
+		Concurrency:   4,
+		RequestConfig: conf,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 40; i++ {
+		if err = writer.Write(rdf.Statement{Subject: rdf.IRI(fmt.Sprintf("http://example.org/s/%d", i)), Predicate: rdf.IRI("http://example.org/p"), Object: rdf.NewLiteral("value")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if stats, err := writer.Close(); err != nil || stats.Batches != 40 {
+		t.Errorf("expected 40 batches, got %+v: %v", stats, err)
+	}
+}
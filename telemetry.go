@@ -0,0 +1,184 @@
+package graphdb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/yaskoo/go-graphdb"
+
+const (
+	AttributeOperation  = attribute.Key("graphdb.operation")
+	AttributeRepository = attribute.Key("graphdb.repository")
+	AttributeQueryHash  = attribute.Key("graphdb.query.hash")
+	AttributeResults    = attribute.Key("graphdb.result.count")
+	AttributeMethod     = attribute.Key("http.request.method")
+	AttributeStatusCode = attribute.Key("http.response.status_code")
+	AttributeServer     = attribute.Key("server.address")
+	AttributeErrorType  = attribute.Key("error.type")
+)
+
+// Instrumentation configures OpenTelemetry tracing and metrics. Nil providers and propagator default to the global ones.
+type Instrumentation struct {
+	TracerProvider trace.TracerProvider
+	MeterProvider  metric.MeterProvider
+	Propagator     propagation.TextMapPropagator
+}
+
+// WithInstrumentation creates a span for every operation, named after it, e.g. cluster_add_node or rdf4j_query, and
+// records the duration, in-flight count and errors of the operations.
+func WithInstrumentation(instrumentation Instrumentation) Option {
+	return func(client *Client) {
+		client.telemetry = newTelemetry(instrumentation)
+	}
+}
+
+type telemetry struct {
+	tracer     trace.Tracer
+	meter      metric.Meter
+	propagator propagation.TextMapPropagator
+	duration   metric.Float64Histogram
+	inFlight   metric.Int64UpDownCounter
+	errors     metric.Int64Counter
+}
+
+func newTelemetry(i Instrumentation) *telemetry {
+	if i.TracerProvider == nil {
+		i.TracerProvider = otel.GetTracerProvider()
+	}
+	if i.MeterProvider == nil {
+		i.MeterProvider = otel.GetMeterProvider()
+	}
+	if i.Propagator == nil {
+		i.Propagator = otel.GetTextMapPropagator()
+	}
+
+	// instrument creation only fails for invalid names, in which case the returned no-op instruments are used
+	meter := i.MeterProvider.Meter(instrumentationName)
+	duration, _ := meter.Float64Histogram("graphdb.client.operation.duration",
+		metric.WithDescription("Duration of GraphDB operations, including retries and reading the response."),
+		metric.WithUnit("s"))
+	inFlight, _ := meter.Int64UpDownCounter("graphdb.client.operation.active",
+		metric.WithDescription("Number of GraphDB operations in flight."))
+	errs, _ := meter.Int64Counter("graphdb.client.operation.errors",
+		metric.WithDescription("Number of failed GraphDB operations."))
+
+	return &telemetry{
+		tracer:     i.TracerProvider.Tracer(instrumentationName),
+		meter:      meter,
+		propagator: i.Propagator,
+		duration:   duration,
+		inFlight:   inFlight,
+		errors:     errs,
+	}
+}
+
+// span is an instrumented operation. All methods are no-ops on a nil span, so instrumentation stays optional.
+type span struct {
+	telemetry *telemetry
+	span      trace.Span
+	start     time.Time
+	attrs     []attribute.KeyValue
+}
+
+// startSpan starts a span for the request and returns the request with the span in its context.
+func (t *telemetry) startSpan(req *http.Request, path string) (*http.Request, *span) {
+	if t == nil {
+		return req, nil
+	}
+
+	op := operation(req, path)
+	attrs := []attribute.KeyValue{AttributeOperation.String(op)}
+	if repo := repositoryId(path); repo != "" {
+		attrs = append(attrs, AttributeRepository.String(repo))
+	}
+
+	ctx, s := t.tracer.Start(req.Context(), op, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	s.SetAttributes(AttributeMethod.String(req.Method), AttributeServer.String(req.URL.Hostname()))
+	if hash := queryHash(req); hash != "" {
+		s.SetAttributes(AttributeQueryHash.String(hash))
+	}
+
+	req = req.WithContext(ctx)
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	t.inFlight.Add(ctx, 1, metric.WithAttributes(attrs...))
+	return req, &span{telemetry: t, span: s, start: time.Now(), attrs: attrs}
+}
+
+func (s *span) response(resp *http.Response) {
+	if s == nil {
+		return
+	}
+	s.span.SetAttributes(AttributeStatusCode.Int(resp.StatusCode))
+}
+
+func (s *span) end(ctx context.Context, err error) {
+	if s == nil {
+		return
+	}
+
+	t := s.telemetry
+	t.inFlight.Add(ctx, -1, metric.WithAttributes(s.attrs...))
+
+	attrs := s.attrs
+	if err != nil {
+		errorType := "error"
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			errorType = strconv.Itoa(apiErr.StatusCode)
+		} else if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			errorType = "canceled"
+		}
+		attrs = append(attrs, AttributeErrorType.String(errorType))
+
+		t.errors.Add(ctx, 1, metric.WithAttributes(attrs...))
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+
+	t.duration.Record(ctx, time.Since(s.start).Seconds(), metric.WithAttributes(attrs...))
+	s.span.End()
+}
+
+// recordResults adds the number of results of an operation to its span.
+func recordResults(resp *http.Response, count int) {
+	if resp.Request == nil {
+		return
+	}
+	trace.SpanFromContext(resp.Request.Context()).SetAttributes(AttributeResults.Int(count))
+}
+
+// queryHash returns a short hash of the SPARQL query or update in the request body, so spans can be grouped by query
+// without recording the query itself.
+func queryHash(req *http.Request) string {
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("content-type"))
+	if (mediaType != "application/sparql-query" && mediaType != "application/sparql-update") || req.GetBody == nil {
+		return ""
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, body); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
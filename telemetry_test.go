@@ -0,0 +1,128 @@
+package graphdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestClient_Instrumentation(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repositories/test":
+			traceparent = r.Header.Get("traceparent")
+			_, _ = w.Write([]byte(`{"results":{"bindings":[{"s":{"value":"urn:a"}},{"s":{"value":"urn:b"}}]}}`))
+		case PathClusterConfigNodes:
+			w.WriteHeader(http.StatusPreconditionFailed)
+			_, _ = w.Write([]byte(`["no cluster"]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	client := New(server.URL, WithInstrumentation(Instrumentation{
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)),
+		MeterProvider:  sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+		Propagator:     propagation.TraceContext{},
+	}))
+
+	ctx := context.Background()
+	if _, err := client.RDF4J().Select(ctx, "test", "SELECT ?s WHERE { ?s ?p ?o }"); err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	if _, err := client.Cluster().AddNodes(ctx, []string{"node-2:7300"}); err == nil {
+		t.Fatal("expected adding nodes to fail")
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 2 || ended[0].Name() != "rdf4j_query" || ended[1].Name() != ClusterAddNodes {
+		t.Fatalf("unexpected spans: %v", ended)
+	}
+
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range ended[0].Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+
+	if attrs[AttributeRepository].AsString() != "test" || attrs[AttributeStatusCode].AsInt64() != http.StatusOK || attrs[AttributeResults].AsInt64() != 2 {
+		t.Errorf("unexpected query span attributes: %v", attrs)
+	}
+	if len(attrs[AttributeQueryHash].AsString()) != 16 {
+		t.Errorf("expected a query hash, got %q", attrs[AttributeQueryHash].AsString())
+	}
+	if traceparent == "" || ended[0].SpanContext().TraceID().String() != traceparent[3:35] {
+		t.Errorf("expected the trace context to be propagated, got %q", traceparent)
+	}
+
+	if ended[1].Status().Code.String() != "Error" {
+		t.Errorf("expected the failed operation span to have error status, got %v", ended[1].Status())
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("failed to collect metrics: %v", err)
+	}
+
+	metrics := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+
+	duration, ok := metrics["graphdb.client.operation.duration"].(metricdata.Histogram[float64])
+	if !ok || len(duration.DataPoints) != 2 {
+		t.Errorf("expected a duration per operation, got %v", metrics["graphdb.client.operation.duration"])
+	}
+
+	errs, ok := metrics["graphdb.client.operation.errors"].(metricdata.Sum[int64])
+	if !ok || len(errs.DataPoints) != 1 || errs.DataPoints[0].Value != 1 {
+		t.Fatalf("expected a single error, got %v", metrics["graphdb.client.operation.errors"])
+	}
+	if op, _ := errs.DataPoints[0].Attributes.Value(AttributeOperation); op.AsString() != ClusterAddNodes {
+		t.Errorf("expected the error to be recorded for %s, got %s", ClusterAddNodes, op.AsString())
+	}
+	if errorType, _ := errs.DataPoints[0].Attributes.Value(AttributeErrorType); errorType.AsString() != "412" {
+		t.Errorf("expected the error type to be the status code, got %s", errorType.AsString())
+	}
+
+	active, ok := metrics["graphdb.client.operation.active"].(metricdata.Sum[int64])
+	if !ok {
+		t.Fatalf("expected an in-flight metric")
+	}
+	for _, dp := range active.DataPoints {
+		if dp.Value != 0 {
+			t.Errorf("expected no operations in flight, got %d", dp.Value)
+		}
+	}
+}
+
+func TestOperation(t *testing.T) {
+	tests := []struct {
+		method, path, query, expected string
+	}{
+		{http.MethodGet, "/rest/repositories/test/size", "", "repository_size"},
+		{http.MethodDelete, PathClusterSecondaryMode, "", ClusterDisableSecondary},
+		{http.MethodPut, "/repositories/test/transactions/abc", "action=COMMIT", "rdf4j_transaction_commit"},
+		{http.MethodGet, "/rest/unknown", "", "http_get"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path+"?"+tt.query, nil)
+		if op := operation(req, tt.path); op != tt.expected {
+			t.Errorf("%s %s: expected %s, got %s", tt.method, tt.path, tt.expected, op)
+		}
+	}
+}
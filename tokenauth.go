@@ -0,0 +1,101 @@
+package graphdb
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTokenValidity is how long GraphDB tokens are valid when the server does not override
+	// graphdb.auth.token.validity.
+	DefaultTokenValidity = 30 * 24 * time.Hour
+
+	// tokenRefreshMargin is how long before its expiry a token is replaced.
+	tokenRefreshMargin = time.Minute
+)
+
+// WithTokenAuth authenticates requests with a GDB token. The client logs in on the first request and logs in again
+// when the token is about to expire or the server rejects it with 401.
+func WithTokenAuth(username, password string) Option {
+	return func(client *Client) {
+		client.credentials = &tokenAuth{client: client, username: username, password: password, validity: DefaultTokenValidity}
+	}
+}
+
+// tokenAuth holds the current token. It is safe for concurrent use, concurrent requests wait for a single login.
+type tokenAuth struct {
+	client   *Client
+	username string
+	password string
+	validity time.Duration
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// Authenticate sets the authorization header, logging in first if there is no valid token.
+func (t *tokenAuth) Authenticate(req *http.Request) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token == "" || (!t.expiry.IsZero() && time.Now().Add(tokenRefreshMargin).After(t.expiry)) {
+		token, _, err := t.client.security.Login(req.Context(), t.username, t.password)
+		if err != nil {
+			return err
+		}
+		t.token = token
+		t.expiry = tokenExpiry(token, t.validity)
+	}
+
+	req.Header.Set("authorization", "GDB "+t.token)
+	return nil
+}
+
+// Refresh drops the token the server rejected, unless another request has already replaced it.
+func (t *tokenAuth) Refresh(req *http.Request) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if req.Header.Get("authorization") == "GDB "+t.token {
+		t.token = ""
+	}
+	return true
+}
+
+// tokenExpiry reads the expiry of a GDB token, whose first part is a base64 encoded JSON with the time the user
+// authenticated at. It returns zero when the token cannot be parsed, so it is only refreshed on 401.
+func tokenExpiry(token string, validity time.Duration) time.Time {
+	payload, _, _ := strings.Cut(token, ".")
+
+	var data []byte
+	var err error
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if data, err = enc.DecodeString(payload); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return time.Time{}
+	}
+
+	var claims struct {
+		AuthenticatedAt int64 `json:"authenticatedAt"`
+		ExpiresAt       int64 `json:"expiresAt"`
+	}
+	if json.Unmarshal(data, &claims) != nil {
+		return time.Time{}
+	}
+
+	switch {
+	case claims.ExpiresAt > 0:
+		return time.UnixMilli(claims.ExpiresAt)
+	case claims.AuthenticatedAt > 0:
+		return time.UnixMilli(claims.AuthenticatedAt).Add(validity)
+	}
+	return time.Time{}
+}
@@ -0,0 +1,155 @@
+package graphdb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tokenServer issues GDB tokens on login and accepts only the latest one.
+type tokenServer struct {
+	mu      sync.Mutex
+	logins  atomic.Int32
+	current string
+	expires time.Duration
+}
+
+func (s *tokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.URL.Path == PathLogin {
+		var creds map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&creds)
+		if creds["username"] != "admin" || creds["password"] != "root" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		n := s.logins.Add(1)
+		claims, _ := json.Marshal(map[string]int64{
+			"authenticatedAt": time.Now().UnixMilli(),
+			"expiresAt":       time.Now().Add(s.expires).UnixMilli(),
+		})
+		s.current = base64.StdEncoding.EncodeToString(claims) + fmt.Sprintf(".signature%d", n)
+		w.Header().Set("authorization", "GDB "+s.current)
+		_, _ = w.Write([]byte(`{"username":"admin","authorities":["ROLE_ADMIN"]}`))
+		return
+	}
+
+	if r.Header.Get("authorization") != "GDB "+s.current {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	_, _ = w.Write([]byte(`[{"username":"admin"}]`))
+}
+
+func (s *tokenServer) revoke() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current = "revoked"
+}
+
+func TestClient_TokenAuth(t *testing.T) {
+	ts := &tokenServer{expires: time.Hour}
+	server := httptest.NewServer(ts)
+	defer server.Close()
+
+	ctx := context.Background()
+	client := New(server.URL, WithTokenAuth("admin", "root"))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.Security().Users(ctx)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("expected request to be authenticated: %v", err)
+		}
+	}
+
+	if n := ts.logins.Load(); n != 1 {
+		t.Errorf("expected a single login for concurrent requests, got %d", n)
+	}
+
+	ts.revoke()
+	if _, err := client.Security().Users(ctx); err != nil {
+		t.Fatalf("expected a new login after 401: %v", err)
+	}
+
+	if n := ts.logins.Load(); n != 2 {
+		t.Errorf("expected a second login, got %d", n)
+	}
+}
+
+func TestClient_TokenAuthExpiry(t *testing.T) {
+	// tokens expiring within the refresh margin are replaced before each request
+	ts := &tokenServer{expires: 30 * time.Second}
+	server := httptest.NewServer(ts)
+	defer server.Close()
+
+	ctx := context.Background()
+	client := New(server.URL, WithTokenAuth("admin", "root"))
+	for range 3 {
+		if _, err := client.Security().Users(ctx); err != nil {
+			t.Fatalf("expected request to be authenticated: %v", err)
+		}
+	}
+
+	if n := ts.logins.Load(); n != 3 {
+		t.Errorf("expected a login per request, got %d", n)
+	}
+
+	client = New(server.URL, WithTokenAuth("admin", "wrong"))
+	if _, err := client.Security().Users(ctx); err == nil {
+		t.Error("expected login with wrong password to fail")
+	}
+}
+
+func TestTokenExpiry(t *testing.T) {
+	at := time.UnixMilli(1700000000000)
+	token := base64.RawURLEncoding.EncodeToString([]byte(`{"username":"admin","authenticatedAt":1700000000000}`)) + ".sig"
+	if expiry := tokenExpiry(token, time.Hour); !expiry.Equal(at.Add(time.Hour)) {
+		t.Errorf("expected expiry %s, got %s", at.Add(time.Hour), expiry)
+	}
+
+	if expiry := tokenExpiry("opaque", time.Hour); !expiry.IsZero() {
+		t.Errorf("expected no expiry for an opaque token, got %s", expiry)
+	}
+}
+
+func TestClient_TokenAuthDryRun(t *testing.T) {
+	ts := &tokenServer{expires: time.Hour}
+	server := httptest.NewServer(ts)
+	defer server.Close()
+
+	var skipped, audited []string
+	client := New(server.URL, WithTokenAuth("admin", "root"),
+		WithMiddleware(
+			DryRun(func(req *http.Request) { skipped = append(skipped, req.URL.Path) }),
+			Audit(func(entry AuditEntry) { audited = append(audited, entry.Path) }),
+		))
+
+	if _, err := client.Security().Users(context.Background()); err != nil {
+		t.Fatalf("expected the login to pass the dry run: %v", err)
+	}
+	if ts.logins.Load() != 1 || len(skipped) != 0 || len(audited) != 0 {
+		t.Errorf("expected the login to be sent and not audited, got %d logins, skipped %v, audited %v", ts.logins.Load(), skipped, audited)
+	}
+}